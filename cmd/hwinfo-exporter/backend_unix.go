@@ -0,0 +1,24 @@
+//go:build unix
+
+package main
+
+import (
+	"flag"
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/hwinfoshmem"
+)
+
+// backendFlags registers the flag for the file containing HWiNFO's shared memory, e.g. kept up to
+// date in /dev/shm by a relay, and returns a function that opens it.
+func backendFlags() func() hwinfoshmem.Backend {
+	path := flag.String("file", "/dev/shm/HWiNFO_SENS_SM2", "file containing HWiNFO's shared memory")
+	lock := flag.Bool("lock", true, "hold an advisory lock on the file while reading it")
+
+	return func() hwinfoshmem.Backend {
+		reader := hwinfoshmem.NewFileReader(*path)
+		if *lock {
+			reader.Locker = hwinfoshmem.NewFileLocker(*path)
+		}
+
+		return reader
+	}
+}
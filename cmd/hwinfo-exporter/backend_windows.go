@@ -0,0 +1,14 @@
+//go:build windows
+
+package main
+
+import (
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/hwinfoshmem"
+)
+
+// backendFlags returns a function that opens HWiNFO's shared memory.
+func backendFlags() func() hwinfoshmem.Backend {
+	return func() hwinfoshmem.Backend {
+		return hwinfoshmem.NewMemoryReader()
+	}
+}
@@ -0,0 +1,44 @@
+//go:build windows || unix
+
+// Command hwinfo-exporter serves HWiNFO's readings as Prometheus metrics.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/exporter/prometheus"
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/hwinfoshmem"
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/selector"
+	"net/http"
+	"os"
+)
+
+func main() {
+	listen := flag.String("listen", ":9183", "address to serve the metrics on")
+	metricsPath := flag.String("path", "/metrics", "path of the metrics endpoint")
+	namespace := flag.String("namespace", prometheus.DefaultNamespace, "prefix of the metric names")
+	statistics := flag.Bool("statistics", false, "also export the minimum, maximum, and average of the readings")
+
+	var readingSelector selector.Selector
+	flag.Var(&readingSelector, "select", "expression selecting the readings to export, e.g. type==temp")
+
+	newBackend := backendFlags()
+	flag.Parse()
+
+	supervisor := hwinfoshmem.NewSupervisor(newBackend)
+	defer supervisor.Close()
+
+	exporter := prometheus.NewExporter(supervisor)
+	exporter.Namespace = *namespace
+	exporter.Selector = &readingSelector
+	exporter.Statistics = *statistics
+
+	http.Handle(*metricsPath, exporter)
+
+	fmt.Printf("Serving metrics on %s%s\n", *listen, *metricsPath)
+	err := http.ListenAndServe(*listen, nil)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
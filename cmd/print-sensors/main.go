@@ -1,3 +1,5 @@
+//go:build windows
+
 package main
 
 import (
@@ -61,5 +63,15 @@ func main() {
 	}
 	copyReader := memoryReader.Copy(hwInfo)
 
-	os.WriteFile("memcopy.bin", copyReader.Bytes, 0666)
+	snapshotFile, err := hwinfoshmem.NewSnapshotFile(copyReader.Bytes).MarshalBinary()
+	if err != nil {
+		fmt.Printf("Error creating snapshot file: %s\n", err)
+		os.Exit(1)
+	}
+
+	err = os.WriteFile("memcopy.bin", snapshotFile, 0666)
+	if err != nil {
+		fmt.Printf("Error writing snapshot file: %s\n", err)
+		os.Exit(1)
+	}
 }
@@ -2,4 +2,7 @@ module github.com/MatthiasKunnen/hwinfo-go
 
 go 1.21
 
-require golang.org/x/sys v0.12.0
+require (
+	golang.org/x/sys v0.12.0
+	golang.org/x/text v0.14.0
+)
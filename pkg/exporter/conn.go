@@ -0,0 +1,153 @@
+package exporter
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultMaxDatagramSize is the default maximum size of the datagrams sent by [Conn], small enough
+// to not be fragmented on typical networks.
+const DefaultMaxDatagramSize = 1400
+
+// DefaultDialTimeout is the default maximum time to wait for a connection.
+const DefaultDialTimeout = 10 * time.Second
+
+// Conn writes lines to a UDP or TCP endpoint.
+//
+// For UDP, every Write is split into datagrams of at most MaxDatagramSize bytes at line boundaries
+// so that receivers never see partial lines. A line that is longer is sent in a datagram of its own.
+//
+// For TCP, the connection is closed when writing fails and dialed again by the next Write, e.g.
+// after the receiver restarted.
+//
+// A Conn can be used concurrently.
+//
+// Conn has an initializer function, [Dial].
+type Conn struct {
+	// Network is the network to connect over, e.g. udp or tcp, see [net.Dial].
+	Network string
+
+	// Address of the endpoint, e.g. localhost:8089.
+	Address string
+
+	// MaxDatagramSize is the maximum size of UDP datagrams.
+	MaxDatagramSize int
+
+	// DialTimeout is the maximum time to wait for a connection.
+	DialTimeout time.Duration
+
+	mutex sync.Mutex
+	conn  net.Conn
+}
+
+// Dial connects to the address on the network, e.g. udp or tcp, see [net.Dial].
+func Dial(network string, address string) (*Conn, error) {
+	conn := &Conn{
+		Network:         network,
+		Address:         address,
+		MaxDatagramSize: DefaultMaxDatagramSize,
+		DialTimeout:     DefaultDialTimeout,
+	}
+
+	if err := conn.dial(); err != nil {
+		return nil, err
+	}
+
+	return conn, nil
+}
+
+// Write sends the lines in data, dialing again when the previous connection failed.
+func (conn *Conn) Write(data []byte) (int, error) {
+	conn.mutex.Lock()
+	defer conn.mutex.Unlock()
+
+	if conn.conn == nil {
+		if err := conn.dial(); err != nil {
+			return 0, err
+		}
+	}
+
+	var written int
+	var err error
+	if conn.isPacket() {
+		written, err = conn.writeDatagrams(data)
+	} else {
+		written, err = conn.conn.Write(data)
+	}
+
+	if err != nil {
+		_ = conn.conn.Close()
+		conn.conn = nil
+		return written, fmt.Errorf("error writing to %s %s: %w", conn.Network, conn.Address, err)
+	}
+
+	return written, nil
+}
+
+// Close closes the connection.
+func (conn *Conn) Close() error {
+	conn.mutex.Lock()
+	defer conn.mutex.Unlock()
+
+	if conn.conn == nil {
+		return nil
+	}
+
+	err := conn.conn.Close()
+	conn.conn = nil
+
+	return err
+}
+
+func (conn *Conn) dial() error {
+	timeout := conn.DialTimeout
+	if timeout <= 0 {
+		timeout = DefaultDialTimeout
+	}
+
+	netConn, err := net.DialTimeout(conn.Network, conn.Address, timeout)
+	if err != nil {
+		return fmt.Errorf("error connecting to %s %s: %w", conn.Network, conn.Address, err)
+	}
+
+	conn.conn = netConn
+	return nil
+}
+
+func (conn *Conn) isPacket() bool {
+	return strings.HasPrefix(conn.Network, "udp") || conn.Network == "unixgram"
+}
+
+// writeDatagrams sends the data in datagrams that end at line boundaries.
+func (conn *Conn) writeDatagrams(data []byte) (int, error) {
+	maxSize := conn.MaxDatagramSize
+	if maxSize <= 0 {
+		maxSize = DefaultMaxDatagramSize
+	}
+
+	written := 0
+	for written < len(data) {
+		size := len(data) - written
+		if size > maxSize {
+			size = bytes.LastIndexByte(data[written:written+maxSize], '\n') + 1
+			if size == 0 {
+				// The line does not fit, send it on its own.
+				size = bytes.IndexByte(data[written:], '\n') + 1
+				if size == 0 {
+					size = len(data) - written
+				}
+			}
+		}
+
+		if _, err := conn.conn.Write(data[written : written+size]); err != nil {
+			return written, err
+		}
+		written += size
+	}
+
+	return written, nil
+}
@@ -0,0 +1,118 @@
+package exporter_test
+
+import (
+	"bufio"
+	"errors"
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/exporter"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConnSplitsDatagramsAtLines(t *testing.T) {
+	listener, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer listener.Close()
+
+	conn, err := exporter.Dial("udp", listener.LocalAddr().String())
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	defer conn.Close()
+	conn.MaxDatagramSize = 10
+
+	data := "aaa\nbbb\ncccccccccccccc\nd\ne\n"
+	written, err := conn.Write([]byte(data))
+	if err != nil || written != len(data) {
+		t.Fatalf("expected to write %d bytes, wrote %d: %v", len(data), written, err)
+	}
+
+	_ = listener.SetReadDeadline(time.Now().Add(5 * time.Second))
+	datagram := make([]byte, 1024)
+	for _, expected := range []string{"aaa\nbbb\n", "cccccccccccccc\n", "d\ne\n"} {
+		size, _, err := listener.ReadFrom(datagram)
+		if err != nil {
+			t.Fatalf("failed to receive: %v", err)
+		}
+
+		if string(datagram[:size]) != expected {
+			t.Errorf("expected datagram %q, got %q", expected, datagram[:size])
+		}
+	}
+}
+
+func TestConnReconnects(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer listener.Close()
+
+	received := make(chan string)
+	go func() {
+		for {
+			accepted, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			line, _ := bufio.NewReader(accepted).ReadString('\n')
+			_ = accepted.Close()
+			received <- line
+		}
+	}()
+
+	conn, err := exporter.Dial("tcp", listener.Addr().String())
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	defer conn.Close()
+
+	if _, err = conn.Write([]byte("first\n")); err != nil {
+		t.Fatalf("failed to write: %v", err)
+	}
+	if line := <-received; line != "first\n" {
+		t.Errorf("expected first line, got %q", line)
+	}
+
+	// The receiver closed the connection, writing eventually fails after which the connection is
+	// dialed again.
+	deadline := time.Now().Add(5 * time.Second)
+	for err == nil && time.Now().Before(deadline) {
+		_, err = conn.Write([]byte("lost\n"))
+		time.Sleep(time.Millisecond)
+	}
+	if err == nil {
+		t.Fatal("expected writing to a closed connection to fail")
+	}
+
+	if _, err = conn.Write([]byte("second\n")); err != nil {
+		t.Fatalf("failed to write after reconnecting: %v", err)
+	}
+
+	select {
+	case line := <-received:
+		if !strings.HasSuffix(line, "second\n") {
+			t.Errorf("expected second line, got %q", line)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for line")
+	}
+}
+
+func TestDialFails(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	address := listener.Addr().String()
+	_ = listener.Close()
+
+	var opError *net.OpError
+	if _, err = exporter.Dial("tcp", address); !errors.As(err, &opError) {
+		t.Errorf("expected net.OpError, got %v", err)
+	}
+}
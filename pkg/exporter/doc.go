@@ -0,0 +1,11 @@
+/*
+Package exporter sends HWiNFO's readings to monitoring systems. The formats are implemented by the
+subpackages:
+  - prometheus: the Prometheus text exposition format, served over HTTP
+  - influxdb: InfluxDB line protocol
+  - plaintext: the plaintext protocols of OpenTSDB and Graphite
+
+The writers of the line based formats write to an [io.Writer], which can be a [Conn] to send them
+to a UDP or TCP endpoint.
+*/
+package exporter
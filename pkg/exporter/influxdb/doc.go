@@ -0,0 +1,11 @@
+/*
+Package influxdb writes HWiNFO's readings in InfluxDB line protocol.
+
+Every reading becomes a line in the measurement of its [hwinfoshmem.ReadingType], tagged with its
+sensor and label, with the value, minimum, maximum, and average as fields:
+
+	hwinfo_temp,instance=0,label=CPU\ (Tctl/Tdie),reading_id=0x1000000,sensor=CPU\ [#0]:\ AMD\ Ryzen\ 9\ 7950X:\ Enhanced,sensor_id=0xf0000300,unit=°C value=47.25,min=40,max=80.5,avg=50 1694966200000000000
+
+The values are converted to the base unit of their unit, see [units.NormalizeReading].
+*/
+package influxdb
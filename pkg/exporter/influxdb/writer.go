@@ -0,0 +1,145 @@
+package influxdb
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/hwinfoshmem"
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/selector"
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/units"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultPrefix is the prefix of the measurement names.
+const DefaultPrefix = "hwinfo_"
+
+// Writer converts snapshots to line protocol, see the package documentation.
+//
+// Writer has an initializer function, [NewWriter].
+type Writer struct {
+	// Prefix of the measurement names, followed by the name of the reading type, e.g. temp.
+	Prefix string
+
+	// Selector selects the readings to write. When nil, all readings are written.
+	Selector *selector.Selector
+
+	// Precision of the timestamps, one of [time.Nanosecond], [time.Microsecond],
+	// [time.Millisecond], or [time.Second]. It must match the precision configured in InfluxDB.
+	Precision time.Duration
+
+	writer io.Writer
+}
+
+// NewWriter creates a writer with the default prefix and nanosecond precision that writes to
+// writer, e.g. an [github.com/MatthiasKunnen/hwinfo-go/pkg/exporter.Conn].
+func NewWriter(writer io.Writer) *Writer {
+	return &Writer{
+		Prefix:    DefaultPrefix,
+		Precision: time.Nanosecond,
+		writer:    writer,
+	}
+}
+
+// Write writes a line for every selected reading of the snapshot in a single call, timestamped
+// with the LastUpdate of the snapshot.
+// Values that are NaN or infinite are omitted, readings without any value are skipped.
+func (writer *Writer) Write(snapshot *hwinfoshmem.Snapshot) error {
+	readings := snapshot.Readings
+	if writer.Selector != nil {
+		readings = writer.Selector.Select(snapshot)
+	}
+
+	precision := writer.Precision
+	if precision <= 0 {
+		precision = time.Nanosecond
+	}
+	timestamp := strconv.FormatInt(snapshot.LastUpdate.UnixNano()/int64(precision), 10)
+
+	var buffer bytes.Buffer
+	for _, reading := range readings {
+		appendLine(&buffer, writer.Prefix, reading, timestamp)
+	}
+
+	if buffer.Len() == 0 {
+		return nil
+	}
+
+	if _, err := writer.writer.Write(buffer.Bytes()); err != nil {
+		return fmt.Errorf("error writing line protocol: %w", err)
+	}
+
+	return nil
+}
+
+// appendLine appends the line of the reading unless it has no values.
+func appendLine(buffer *bytes.Buffer, prefix string, reading *hwinfoshmem.Reading, timestamp string) {
+	normalized := units.NormalizeReading(reading)
+
+	fields := make([]string, 0, 4)
+	for _, field := range []struct {
+		key   string
+		value float64
+	}{
+		{"value", normalized.Value},
+		{"min", normalized.Min},
+		{"max", normalized.Max},
+		{"avg", normalized.Avg},
+	} {
+		if math.IsNaN(field.value) || math.IsInf(field.value, 0) {
+			continue
+		}
+
+		fields = append(fields, field.key+"="+strconv.FormatFloat(field.value, 'f', -1, 64))
+	}
+
+	if len(fields) == 0 {
+		return
+	}
+
+	var sensorName string
+	var sensorId, sensorInstance uint32
+	if reading.Sensor != nil {
+		sensorName = reading.Sensor.SensorName
+		sensorId = reading.Sensor.SensorId
+		sensorInstance = reading.Sensor.SensorInstance
+	}
+
+	buffer.WriteString(measurementEscaper.Replace(prefix + reading.Type.String()))
+
+	// Sorted by key, which InfluxDB recommends for performance.
+	for _, tag := range [][2]string{
+		{"instance", strconv.FormatUint(uint64(sensorInstance), 10)},
+		{"label", reading.UserLabel},
+		{"reading_id", fmt.Sprintf("%#x", reading.Id)},
+		{"sensor", sensorName},
+		{"sensor_id", fmt.Sprintf("%#x", sensorId)},
+		{"unit", normalized.BaseUnit},
+	} {
+		// A trailing backslash would escape the separator that follows.
+		value := strings.TrimRight(tag[1], `\`)
+		if value == "" {
+			// Empty tag values are not allowed.
+			continue
+		}
+
+		buffer.WriteByte(',')
+		buffer.WriteString(tag[0])
+		buffer.WriteByte('=')
+		buffer.WriteString(tagEscaper.Replace(value))
+	}
+
+	buffer.WriteByte(' ')
+	buffer.WriteString(strings.Join(fields, ","))
+	buffer.WriteByte(' ')
+	buffer.WriteString(timestamp)
+	buffer.WriteByte('\n')
+}
+
+// measurementEscaper escapes measurement names. Line breaks cannot be escaped and are replaced.
+var measurementEscaper = strings.NewReplacer(",", `\,`, " ", `\ `, "\n", `\ `, "\r", "")
+
+// tagEscaper escapes tag keys and values. Line breaks cannot be escaped and are replaced.
+var tagEscaper = strings.NewReplacer(",", `\,`, "=", `\=`, " ", `\ `, "\n", `\ `, "\r", "")
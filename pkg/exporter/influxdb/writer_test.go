@@ -0,0 +1,117 @@
+package influxdb_test
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/exporter"
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/exporter/influxdb"
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/hwinfoshmem"
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/selector"
+	"net"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestSnapshot reads the copy of the shared memory in testdata/mixed_units.bin of the exporter
+// package, see mixed_units.txt.
+func newTestSnapshot() (*hwinfoshmem.Snapshot, error) {
+	image, err := os.ReadFile("../testdata/mixed_units.bin")
+	if err != nil {
+		return nil, err
+	}
+
+	bytesReader := hwinfoshmem.NewBytesReader(image)
+	info, err := bytesReader.GetHeader()
+	if err != nil {
+		return nil, err
+	}
+
+	return hwinfoshmem.NewSnapshot(bytesReader, info)
+}
+
+func ExampleWriter() {
+	writer := influxdb.NewWriter(os.Stdout)
+	writer.Selector = selector.MustCompile("type==temp")
+	writer.Precision = time.Second
+
+	snapshot, err := newTestSnapshot()
+	if err != nil {
+		fmt.Printf("Failed to read snapshot: %s\n", err)
+		return
+	}
+
+	if err = writer.Write(snapshot); err != nil {
+		fmt.Printf("Failed to write: %s\n", err)
+	}
+
+	// Output:
+	// hwinfo_temp,instance=0,label=CPU\ (Tctl/Tdie),reading_id=0x1000000,sensor=CPU\ [#0]:\ AMD\ Ryzen\ 9\ 7950X:\ Enhanced,sensor_id=0xf0000300,unit=°C value=47.25,min=40,max=80.5,avg=50 1694966200
+}
+
+func TestWriter(t *testing.T) {
+	var buffer bytes.Buffer
+	writer := influxdb.NewWriter(&buffer)
+	writer.Prefix = "pc "
+
+	snapshot, err := newTestSnapshot()
+	if err != nil {
+		t.Fatalf("failed to read snapshot: %v", err)
+	}
+
+	// Line breaks cannot be escaped.
+	snapshot.Readings[3].UserLabel = "GPU Core\nVoltage"
+
+	if err = writer.Write(snapshot); err != nil {
+		t.Fatalf("failed to write: %v", err)
+	}
+
+	expected := strings.Join([]string{
+		`pc\ temp,instance=0,label=CPU\ (Tctl/Tdie),reading_id=0x1000000,sensor=CPU\ [#0]:\ AMD\ Ryzen\ 9\ 7950X:\ Enhanced,sensor_id=0xf0000300,unit=°C value=47.25,min=40,max=80.5,avg=50 1694966200000000000`,
+		`pc\ clock,instance=0,label=Core\ 0\ Clock,reading_id=0x6000000,sensor=CPU\ [#0]:\ AMD\ Ryzen\ 9\ 7950X:\ Enhanced,sensor_id=0xf0000300,unit=Hz value=4200000000,min=3000000000,max=5500000000,avg=4500000000 1694966200000000000`,
+		`pc\ other,instance=0,label=Thermal\ Throttling\ (HTC),reading_id=0x7000000,sensor=CPU\ [#0]:\ AMD\ Ryzen\ 9\ 7950X:\ Enhanced,sensor_id=0xf0000300,unit=Yes/No value=0,min=0,max=1,avg=0.5 1694966200000000000`,
+		`pc\ volt,instance=1,label=GPU\ Core\ Voltage,reading_id=0x2000000,sensor=GPU\ [#0]:\ "Primary"\,\ bus\=1,sensor_id=0xe0002000,unit=V value=1.25,min=1.2,max=1.3,avg=1.25 1694966200000000000`,
+		`pc\ other,instance=1,label=GPU\ Memory\ Allocated,reading_id=0x9000000,sensor=GPU\ [#0]:\ "Primary"\,\ bus\=1,sensor_id=0xe0002000,unit=B value=2147483648,min=1073741824,max=4294967296,avg=3221225472 1694966200000000000`,
+		`pc\ other,instance=1,label=Screenshot\ Path\ C:,reading_id=0x9000001,sensor=GPU\ [#0]:\ "Primary"\,\ bus\=1,sensor_id=0xe0002000 value=1 1694966200000000000`,
+		``,
+	}, "\n")
+
+	if buffer.String() != expected {
+		t.Errorf("unexpected lines:\n%s\nexpected:\n%s", buffer.String(), expected)
+	}
+}
+
+func TestWriterUDP(t *testing.T) {
+	snapshot, err := newTestSnapshot()
+	if err != nil {
+		t.Fatalf("failed to read snapshot: %v", err)
+	}
+
+	listener, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer listener.Close()
+
+	conn, err := exporter.Dial("udp", listener.LocalAddr().String())
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	defer conn.Close()
+
+	if err = influxdb.NewWriter(conn).Write(snapshot); err != nil {
+		t.Fatalf("failed to write: %v", err)
+	}
+
+	_ = listener.SetReadDeadline(time.Now().Add(5 * time.Second))
+	datagram := make([]byte, 65536)
+	size, _, err := listener.ReadFrom(datagram)
+	if err != nil {
+		t.Fatalf("failed to receive: %v", err)
+	}
+
+	if lines := strings.Count(string(datagram[:size]), "\n"); lines != 6 {
+		t.Errorf("expected 6 lines, got %q", datagram[:size])
+	}
+}
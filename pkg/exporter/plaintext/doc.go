@@ -0,0 +1,18 @@
+/*
+Package plaintext writes HWiNFO's readings in the plaintext protocols of OpenTSDB and Graphite.
+
+Every reading becomes a metric named after its [hwinfoshmem.ReadingType] and the base unit of its
+unit, tagged with its sensor and label. When enabled, the minimum, maximum, and average are written
+as metrics with the .min, .max, and .avg suffix. E.g. for OpenTSDB:
+
+	put hwinfo.temp.celsius 1694966200000 47.25 instance=0 label=CPU_Tctl/Tdie reading_id=0x1000000 sensor=CPU_0_AMD_Ryzen_9_7950X_Enhanced sensor_id=0xf0000300
+
+And for Graphite, using tags:
+
+	hwinfo.temp.celsius;instance=0;label=CPU_Tctl/Tdie;reading_id=0x1000000;sensor=CPU_0_AMD_Ryzen_9_7950X_Enhanced;sensor_id=0xf0000300 47.25 1694966200
+
+Both protocols only allow a few characters in names and tags. Other characters, such as spaces, are
+replaced by underscores. The values are converted to the base unit of their unit, see
+[units.NormalizeReading].
+*/
+package plaintext
@@ -0,0 +1,182 @@
+package plaintext
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/hwinfoshmem"
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/selector"
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/units"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// DefaultPrefix is the prefix of the metric names.
+const DefaultPrefix = "hwinfo."
+
+// Protocol is a plaintext protocol.
+type Protocol int
+
+const (
+	// ProtocolOpenTSDB is OpenTSDB's telnet style put command, with timestamps in milliseconds.
+	ProtocolOpenTSDB Protocol = iota
+
+	// ProtocolGraphite is Graphite's plaintext protocol with tags, with timestamps in seconds.
+	ProtocolGraphite
+)
+
+func (protocol Protocol) String() string {
+	switch protocol {
+	case ProtocolOpenTSDB:
+		return "opentsdb"
+	case ProtocolGraphite:
+		return "graphite"
+	default:
+		return fmt.Sprintf("Protocol(%d)", int(protocol))
+	}
+}
+
+// Writer converts snapshots to lines of the protocol, see the package documentation.
+//
+// Writer has an initializer function, [NewWriter].
+type Writer struct {
+	// Protocol to write.
+	Protocol Protocol
+
+	// Prefix of the metric names, followed by the name of the reading type, e.g. temp.
+	Prefix string
+
+	// Selector selects the readings to write. When nil, all readings are written.
+	Selector *selector.Selector
+
+	// Statistics enables writing the ValueMin, ValueMax, and ValueAvg of the readings.
+	Statistics bool
+
+	writer io.Writer
+}
+
+// NewWriter creates a writer for the protocol with the default prefix that writes to writer, e.g.
+// an [github.com/MatthiasKunnen/hwinfo-go/pkg/exporter.Conn].
+func NewWriter(writer io.Writer, protocol Protocol) *Writer {
+	return &Writer{
+		Protocol: protocol,
+		Prefix:   DefaultPrefix,
+		writer:   writer,
+	}
+}
+
+// Write writes the selected readings of the snapshot in a single call, timestamped with the
+// LastUpdate of the snapshot. Values that are NaN or infinite are skipped.
+func (writer *Writer) Write(snapshot *hwinfoshmem.Snapshot) error {
+	readings := snapshot.Readings
+	if writer.Selector != nil {
+		readings = writer.Selector.Select(snapshot)
+	}
+
+	var timestamp string
+	switch writer.Protocol {
+	case ProtocolOpenTSDB:
+		timestamp = strconv.FormatInt(snapshot.LastUpdate.UnixMilli(), 10)
+	case ProtocolGraphite:
+		timestamp = strconv.FormatInt(snapshot.LastUpdate.Unix(), 10)
+	default:
+		return fmt.Errorf("unsupported protocol %s", writer.Protocol)
+	}
+
+	var buffer bytes.Buffer
+	for _, reading := range readings {
+		writer.appendLines(&buffer, reading, timestamp)
+	}
+
+	if buffer.Len() == 0 {
+		return nil
+	}
+
+	if _, err := writer.writer.Write(buffer.Bytes()); err != nil {
+		return fmt.Errorf("error writing %s: %w", writer.Protocol, err)
+	}
+
+	return nil
+}
+
+// statistic is a value of a reading with the suffix of its metric name.
+type statistic struct {
+	suffix string
+	value  float64
+}
+
+// appendLines appends the lines of the reading.
+func (writer *Writer) appendLines(buffer *bytes.Buffer, reading *hwinfoshmem.Reading, timestamp string) {
+	normalized := units.NormalizeReading(reading)
+
+	metric := writer.Prefix + reading.Type.String()
+	if suffix := normalized.Unit.Dimension.MetricSuffix(); suffix != "" {
+		metric += "." + suffix
+	}
+	metric = sanitize(metric)
+
+	var sensorName string
+	var sensorId, sensorInstance uint32
+	if reading.Sensor != nil {
+		sensorName = reading.Sensor.SensorName
+		sensorId = reading.Sensor.SensorId
+		sensorInstance = reading.Sensor.SensorInstance
+	}
+
+	tags := make([]string, 0, 5)
+	for _, tag := range [][2]string{
+		{"instance", strconv.FormatUint(uint64(sensorInstance), 10)},
+		{"label", sanitize(reading.UserLabel)},
+		{"reading_id", fmt.Sprintf("%#x", reading.Id)},
+		{"sensor", sanitize(sensorName)},
+		{"sensor_id", fmt.Sprintf("%#x", sensorId)},
+	} {
+		if tag[1] != "" {
+			tags = append(tags, tag[0]+"="+tag[1])
+		}
+	}
+
+	values := []statistic{{"", normalized.Value}}
+	if writer.Statistics {
+		values = append(values, statistic{".min", normalized.Min}, statistic{".max", normalized.Max}, statistic{".avg", normalized.Avg})
+	}
+
+	for _, value := range values {
+		if math.IsNaN(value.value) || math.IsInf(value.value, 0) {
+			continue
+		}
+
+		formatted := strconv.FormatFloat(value.value, 'f', -1, 64)
+		switch writer.Protocol {
+		case ProtocolOpenTSDB:
+			fmt.Fprintf(buffer, "put %s%s %s %s %s\n", metric, value.suffix, timestamp, formatted, strings.Join(tags, " "))
+		case ProtocolGraphite:
+			fmt.Fprintf(buffer, "%s%s;%s %s %s\n", metric, value.suffix, strings.Join(tags, ";"), formatted, timestamp)
+		}
+	}
+}
+
+// sanitize replaces runs of characters that are not allowed in names and tags by a single
+// underscore, trimming them at the start and end.
+// Allowed are letters, digits, and the characters - _ . /.
+func sanitize(s string) string {
+	var builder strings.Builder
+	replaced := false
+
+	for _, r := range s {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("-_./", r) {
+			if replaced && builder.Len() > 0 {
+				builder.WriteByte('_')
+			}
+			replaced = false
+			builder.WriteRune(r)
+			continue
+		}
+
+		replaced = true
+	}
+
+	return builder.String()
+}
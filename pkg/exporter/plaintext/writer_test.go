@@ -0,0 +1,149 @@
+package plaintext_test
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/exporter"
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/exporter/plaintext"
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/hwinfoshmem"
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/selector"
+	"net"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestSnapshot reads the copy of the shared memory in testdata/mixed_units.bin of the exporter
+// package, see mixed_units.txt.
+func newTestSnapshot() (*hwinfoshmem.Snapshot, error) {
+	image, err := os.ReadFile("../testdata/mixed_units.bin")
+	if err != nil {
+		return nil, err
+	}
+
+	bytesReader := hwinfoshmem.NewBytesReader(image)
+	info, err := bytesReader.GetHeader()
+	if err != nil {
+		return nil, err
+	}
+
+	return hwinfoshmem.NewSnapshot(bytesReader, info)
+}
+
+func ExampleWriter() {
+	snapshot, err := newTestSnapshot()
+	if err != nil {
+		fmt.Printf("Failed to read snapshot: %s\n", err)
+		return
+	}
+
+	// OpenTSDB timestamps are in milliseconds, Graphite timestamps in seconds.
+	snapshot.LastUpdate = snapshot.LastUpdate.Add(123 * time.Millisecond)
+
+	for _, protocol := range []plaintext.Protocol{plaintext.ProtocolOpenTSDB, plaintext.ProtocolGraphite} {
+		writer := plaintext.NewWriter(os.Stdout, protocol)
+		writer.Selector = selector.MustCompile(`sensor~"CPU*"`)
+
+		if err = writer.Write(snapshot); err != nil {
+			fmt.Printf("Failed to write: %s\n", err)
+		}
+	}
+
+	// Output:
+	// put hwinfo.temp.celsius 1694966200123 47.25 instance=0 label=CPU_Tctl/Tdie reading_id=0x1000000 sensor=CPU_0_AMD_Ryzen_9_7950X_Enhanced sensor_id=0xf0000300
+	// put hwinfo.clock.hertz 1694966200123 4200000000 instance=0 label=Core_0_Clock reading_id=0x6000000 sensor=CPU_0_AMD_Ryzen_9_7950X_Enhanced sensor_id=0xf0000300
+	// put hwinfo.other 1694966200123 0 instance=0 label=Thermal_Throttling_HTC reading_id=0x7000000 sensor=CPU_0_AMD_Ryzen_9_7950X_Enhanced sensor_id=0xf0000300
+	// hwinfo.temp.celsius;instance=0;label=CPU_Tctl/Tdie;reading_id=0x1000000;sensor=CPU_0_AMD_Ryzen_9_7950X_Enhanced;sensor_id=0xf0000300 47.25 1694966200
+	// hwinfo.clock.hertz;instance=0;label=Core_0_Clock;reading_id=0x6000000;sensor=CPU_0_AMD_Ryzen_9_7950X_Enhanced;sensor_id=0xf0000300 4200000000 1694966200
+	// hwinfo.other;instance=0;label=Thermal_Throttling_HTC;reading_id=0x7000000;sensor=CPU_0_AMD_Ryzen_9_7950X_Enhanced;sensor_id=0xf0000300 0 1694966200
+}
+
+func TestWriterStatistics(t *testing.T) {
+	var buffer bytes.Buffer
+	writer := plaintext.NewWriter(&buffer, plaintext.ProtocolGraphite)
+	writer.Prefix = "pc #1."
+	writer.Statistics = true
+
+	snapshot, err := newTestSnapshot()
+	if err != nil {
+		t.Fatalf("failed to read snapshot: %v", err)
+	}
+
+	if err = writer.Write(snapshot); err != nil {
+		t.Fatalf("failed to write: %v", err)
+	}
+
+	var metrics []string
+	for _, line := range strings.Split(strings.TrimSpace(buffer.String()), "\n") {
+		name, _, _ := strings.Cut(line, ";")
+		metrics = append(metrics, name)
+	}
+
+	// The unavailable GPU Power is skipped, as are the statistics of the screenshot path.
+	expected := "pc_1.temp.celsius pc_1.temp.celsius.min pc_1.temp.celsius.max pc_1.temp.celsius.avg " +
+		"pc_1.clock.hertz pc_1.clock.hertz.min pc_1.clock.hertz.max pc_1.clock.hertz.avg " +
+		"pc_1.other pc_1.other.min pc_1.other.max pc_1.other.avg " +
+		"pc_1.volt.volts pc_1.volt.volts.min pc_1.volt.volts.max pc_1.volt.volts.avg " +
+		"pc_1.other.bytes pc_1.other.bytes.min pc_1.other.bytes.max pc_1.other.bytes.avg " +
+		"pc_1.other"
+	if strings.Join(metrics, " ") != expected {
+		t.Errorf("expected metrics %s, got %s", expected, metrics)
+	}
+}
+
+func TestWriterTCP(t *testing.T) {
+	snapshot, err := newTestSnapshot()
+	if err != nil {
+		t.Fatalf("failed to read snapshot: %v", err)
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer listener.Close()
+
+	received := make(chan []string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			close(received)
+			return
+		}
+		defer conn.Close()
+
+		var lines []string
+		scanner := bufio.NewScanner(conn)
+		for scanner.Scan() {
+			lines = append(lines, scanner.Text())
+		}
+		received <- lines
+	}()
+
+	conn, err := exporter.Dial("tcp", listener.Addr().String())
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+
+	writer := plaintext.NewWriter(conn, plaintext.ProtocolOpenTSDB)
+	for i := 0; i < 2; i++ {
+		if err = writer.Write(snapshot); err != nil {
+			t.Fatalf("failed to write: %v", err)
+		}
+	}
+
+	if err = conn.Close(); err != nil {
+		t.Fatalf("failed to close: %v", err)
+	}
+
+	select {
+	case lines := <-received:
+		if len(lines) != 12 || !strings.HasPrefix(lines[7], "put hwinfo.clock.hertz ") {
+			t.Errorf("expected 12 lines, got %q", lines)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for lines")
+	}
+}
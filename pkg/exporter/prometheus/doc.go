@@ -0,0 +1,15 @@
+/*
+Package prometheus exposes HWiNFO's readings as metrics in the Prometheus text exposition format.
+
+Every reading becomes a gauge named after its [hwinfoshmem.ReadingType] and the base unit of its
+unit, e.g. hwinfo_temp_celsius and hwinfo_clock_hertz. Values are converted to the base unit, see
+[units.NormalizeReading], so that readings are comparable regardless of the units HWiNFO is
+configured to use. E.g. a clock reported in MHz is exported in Hz.
+
+	hwinfo_temp_celsius{sensor="CPU [#0]: AMD Ryzen 9 7950X: Enhanced",sensor_id="0xf0000300",instance="0",reading_id="0x1000000",label="CPU (Tctl/Tdie)"} 47.25
+
+In addition, the following metrics describe HWiNFO itself:
+  - hwinfo_up: 1 when HWiNFO is active, see [hwinfoshmem.HwinfoHeader.IsActive], 0 otherwise
+  - hwinfo_last_update_age_seconds: the time since HWiNFO last updated the readings
+*/
+package prometheus
@@ -0,0 +1,271 @@
+package prometheus
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/hwinfoshmem"
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/selector"
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/units"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ContentType is the content type of the text exposition format.
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// DefaultNamespace is the prefix of the metric names.
+const DefaultNamespace = "hwinfo"
+
+// Exporter reads the source on every scrape and writes the readings as metrics, see the package
+// documentation.
+// Exporter implements [http.Handler] so that it can be served as the /metrics endpoint.
+// An Exporter can be used concurrently, scrapes are serialized.
+//
+// Exporter has an initializer function, [NewExporter].
+type Exporter struct {
+	// Source to read. When it implements [hwinfoshmem.Locker], e.g. [hwinfoshmem.Supervisor], the
+	// lock is held while reading.
+	Source hwinfoshmem.Source
+
+	// Namespace is the prefix of the metric names, e.g. hwinfo.
+	Namespace string
+
+	// Selector selects the readings to export. When nil, all readings are exported.
+	Selector *selector.Selector
+
+	// Statistics enables exporting ValueMin, ValueMax, and ValueAvg of the readings as metrics with
+	// the _min, _max, and _avg suffix.
+	Statistics bool
+
+	// Now returns the current time, used to calculate the age of the last update. Can be replaced
+	// to control time in tests.
+	Now func() time.Time
+
+	mutex sync.Mutex
+}
+
+var _ http.Handler = (*Exporter)(nil)
+
+// NewExporter creates an exporter for the source with the default namespace.
+func NewExporter(source hwinfoshmem.Source) *Exporter {
+	return &Exporter{
+		Source:    source,
+		Namespace: DefaultNamespace,
+		Now:       time.Now,
+	}
+}
+
+// family is a metric with its samples.
+type family struct {
+	name    string
+	help    string
+	samples []sample
+}
+
+// sample is a value of a metric with its labels, formatted as {name="value",...}.
+type sample struct {
+	labels string
+	value  float64
+}
+
+// metrics collects families in the order they are first added.
+type metrics struct {
+	families []*family
+	byName   map[string]*family
+}
+
+func (m *metrics) add(name string, help string, labels string, value float64) {
+	f, ok := m.byName[name]
+	if !ok {
+		f = &family{name: name, help: help}
+		m.byName[name] = f
+		m.families = append(m.families, f)
+	}
+
+	f.samples = append(f.samples, sample{labels: labels, value: value})
+}
+
+// Write reads the source and writes the metrics in the text exposition format.
+// When the source cannot be read, e.g. because HWiNFO is not running, only hwinfo_up is written
+// with value 0 and nil is returned. Errors writing to writer are returned.
+func (exporter *Exporter) Write(writer io.Writer) error {
+	exporter.mutex.Lock()
+	defer exporter.mutex.Unlock()
+
+	collected := &metrics{byName: make(map[string]*family)}
+	up := 0.0
+
+	snapshot, err := exporter.readSnapshot()
+	if err == nil {
+		if snapshot.Active {
+			up = 1
+		}
+
+		exporter.collect(collected, snapshot)
+	}
+
+	var buffer bytes.Buffer
+	writeFamily(&buffer, &family{
+		name:    exporter.metricName("up"),
+		help:    "Whether HWiNFO is active and its readings could be read.",
+		samples: []sample{{value: up}},
+	})
+
+	for _, f := range collected.families {
+		writeFamily(&buffer, f)
+	}
+
+	if _, err = writer.Write(buffer.Bytes()); err != nil {
+		return fmt.Errorf("error writing metrics: %w", err)
+	}
+
+	return nil
+}
+
+// ServeHTTP writes the metrics as the response.
+func (exporter *Exporter) ServeHTTP(writer http.ResponseWriter, _ *http.Request) {
+	writer.Header().Set("Content-Type", ContentType)
+	_ = exporter.Write(writer)
+}
+
+// readSnapshot creates a snapshot of the source while holding its lock.
+func (exporter *Exporter) readSnapshot() (snapshot *hwinfoshmem.Snapshot, err error) {
+	if locker, ok := exporter.Source.(hwinfoshmem.Locker); ok {
+		if err = locker.Lock(); err != nil {
+			return nil, err
+		}
+		defer func() {
+			err = errors.Join(err, locker.ReleaseLock())
+		}()
+	}
+
+	info, err := exporter.Source.GetHeader()
+	if err != nil {
+		return nil, err
+	}
+
+	return hwinfoshmem.NewSnapshot(exporter.Source, info)
+}
+
+// collect adds the metrics of the snapshot.
+func (exporter *Exporter) collect(collected *metrics, snapshot *hwinfoshmem.Snapshot) {
+	now := exporter.Now
+	if now == nil {
+		now = time.Now
+	}
+
+	collected.add(
+		exporter.metricName("last_update_age_seconds"),
+		"Time since HWiNFO last updated the readings.",
+		"",
+		now().Sub(snapshot.LastUpdate).Seconds(),
+	)
+
+	readings := snapshot.Readings
+	if exporter.Selector != nil {
+		readings = exporter.Selector.Select(snapshot)
+	}
+
+	for _, reading := range readings {
+		normalized := units.NormalizeReading(reading)
+		name := exporter.metricName(reading.Type.String())
+		if suffix := normalized.Unit.Dimension.MetricSuffix(); suffix != "" {
+			name += "_" + suffix
+		}
+
+		help := fmt.Sprintf("HWiNFO %s readings", reading.Type)
+		if baseUnit := normalized.Unit.Dimension.BaseUnit(); baseUnit != "" {
+			help += " in " + baseUnit
+		}
+		help += "."
+
+		labels := readingLabels(reading)
+		collected.add(name, help, labels, normalized.Value)
+
+		if exporter.Statistics {
+			collected.add(name+"_min", "Minimum of "+help, labels, normalized.Min)
+			collected.add(name+"_max", "Maximum of "+help, labels, normalized.Max)
+			collected.add(name+"_avg", "Average of "+help, labels, normalized.Avg)
+		}
+	}
+}
+
+// metricName returns the name prefixed with the namespace, replacing characters that are not
+// allowed in metric names by underscores, e.g. type(12) becomes type_12.
+func (exporter *Exporter) metricName(name string) string {
+	if exporter.Namespace != "" {
+		name = exporter.Namespace + "_" + name
+	}
+
+	var builder strings.Builder
+	for i, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r == '_', r == ':', r >= '0' && r <= '9' && i > 0:
+			builder.WriteRune(r)
+		default:
+			builder.WriteRune('_')
+		}
+	}
+
+	return strings.TrimRight(builder.String(), "_")
+}
+
+// readingLabels returns the labels identifying the reading.
+func readingLabels(reading *hwinfoshmem.Reading) string {
+	var sensorName string
+	var sensorId, sensorInstance uint32
+	if reading.Sensor != nil {
+		sensorName = reading.Sensor.SensorName
+		sensorId = reading.Sensor.SensorId
+		sensorInstance = reading.Sensor.SensorInstance
+	}
+
+	return fmt.Sprintf(
+		`{sensor="%s",sensor_id="%#x",instance="%d",reading_id="%#x",label="%s"}`,
+		escapeLabelValue(sensorName),
+		sensorId,
+		sensorInstance,
+		reading.Id,
+		escapeLabelValue(reading.UserLabel),
+	)
+}
+
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabelValue(value string) string {
+	return labelValueEscaper.Replace(value)
+}
+
+var helpEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+
+func writeFamily(buffer *bytes.Buffer, f *family) {
+	fmt.Fprintf(buffer, "# HELP %s %s\n", f.name, helpEscaper.Replace(f.help))
+	fmt.Fprintf(buffer, "# TYPE %s gauge\n", f.name)
+
+	for _, s := range f.samples {
+		buffer.WriteString(f.name)
+		buffer.WriteString(s.labels)
+		buffer.WriteByte(' ')
+		buffer.WriteString(formatValue(s.value))
+		buffer.WriteByte('\n')
+	}
+}
+
+func formatValue(value float64) string {
+	switch {
+	case math.IsNaN(value):
+		return "NaN"
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	default:
+		return strconv.FormatFloat(value, 'g', -1, 64)
+	}
+}
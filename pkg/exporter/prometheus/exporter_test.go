@@ -0,0 +1,249 @@
+package prometheus_test
+
+import (
+	"errors"
+	"fmt"
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/exporter/prometheus"
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/hwinfoshmem"
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/selector"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+var lastUpdate = time.Unix(1694966200, 0)
+
+// newTestReader returns a BytesReader of a copy of the shared memory with readings in units that
+// need to be converted.
+func newTestReader(t testing.TB, active bool) *hwinfoshmem.BytesReader {
+	cpu := &hwinfoshmem.Sensor{SensorId: 0xf0000300, SensorName: "CPU [#0]: AMD Ryzen 9 7950X: Enhanced"}
+	gpu := &hwinfoshmem.Sensor{SensorId: 0xe0002000, SensorInstance: 1, SensorName: `GPU "Primary"`}
+
+	readings := []*hwinfoshmem.Reading{
+		{Sensor: cpu, Type: hwinfoshmem.SENSOR_TYPE_TEMP, Id: 0x1000000, UserLabel: "CPU (Tctl/Tdie)", Unit: "°C", Value: 47.25, ValueMin: 40, ValueMax: 80.5, ValueAvg: 50},
+		{Sensor: cpu, Type: hwinfoshmem.SENSOR_TYPE_CLOCK, Id: 0x6000000, UserLabel: "Core 0 Clock", Unit: "MHz", Value: 4200, ValueMin: 3000, ValueMax: 5500, ValueAvg: 4500},
+		{Sensor: cpu, Type: hwinfoshmem.SENSOR_TYPE_OTHER, Id: 0x7000000, UserLabel: "Thermal Throttling (HTC)", Unit: "Yes/No", Value: 0, ValueMin: 0, ValueMax: 1, ValueAvg: 0.5},
+		{Sensor: gpu, Type: hwinfoshmem.SENSOR_TYPE_OTHER, Id: 0x9000000, UserLabel: "GPU Memory Allocated", Unit: "MB", Value: 2048, ValueMin: 1024, ValueMax: 4096, ValueAvg: 3072},
+	}
+	cpu.Readings = readings[:3]
+	gpu.Readings = readings[3:]
+
+	image, err := hwinfoshmem.EncodeSnapshot(&hwinfoshmem.Snapshot{
+		Active:     active,
+		Version:    2,
+		Revision:   1,
+		LastUpdate: lastUpdate,
+		Sensors:    []*hwinfoshmem.Sensor{cpu, gpu},
+		Readings:   readings,
+	}, 1252)
+	if err != nil {
+		t.Fatalf("failed to encode snapshot: %v", err)
+	}
+
+	return hwinfoshmem.NewBytesReader(image)
+}
+
+func newTestExporter(source hwinfoshmem.Source) *prometheus.Exporter {
+	exporter := prometheus.NewExporter(source)
+	exporter.Now = func() time.Time {
+		return lastUpdate.Add(1500 * time.Millisecond)
+	}
+
+	return exporter
+}
+
+func scrape(t *testing.T, exporter *prometheus.Exporter) string {
+	t.Helper()
+
+	var builder strings.Builder
+	if err := exporter.Write(&builder); err != nil {
+		t.Fatalf("failed to write metrics: %v", err)
+	}
+
+	return builder.String()
+}
+
+func ExampleExporter() {
+	image, err := os.ReadFile("../../hwinfoshmem/testdata/limited_live.bin")
+	if err != nil {
+		fmt.Printf("Failed to read copy of the shared memory: %s\n", err)
+		return
+	}
+
+	exporter := prometheus.NewExporter(hwinfoshmem.NewBytesReader(image))
+	exporter.Selector = selector.MustCompile(`label~"CPU CCD*"`)
+	exporter.Now = func() time.Time {
+		return time.Unix(1694966202, 0)
+	}
+
+	// Use http.Handle("/metrics", exporter) to serve the metrics.
+	if err = exporter.Write(os.Stdout); err != nil {
+		fmt.Printf("Failed to write metrics: %s\n", err)
+	}
+
+	// Output:
+	// # HELP hwinfo_up Whether HWiNFO is active and its readings could be read.
+	// # TYPE hwinfo_up gauge
+	// hwinfo_up 1
+	// # HELP hwinfo_last_update_age_seconds Time since HWiNFO last updated the readings.
+	// # TYPE hwinfo_last_update_age_seconds gauge
+	// hwinfo_last_update_age_seconds 2
+	// # HELP hwinfo_temp_celsius HWiNFO temp readings in °C.
+	// # TYPE hwinfo_temp_celsius gauge
+	// hwinfo_temp_celsius{sensor="CPU [#0]: AMD Ryzen 9 7950X: Enhanced",sensor_id="0xf0000501",instance="0",reading_id="0x1000008",label="CPU CCD1 (Tdie)"} 45.125
+	// hwinfo_temp_celsius{sensor="CPU [#0]: AMD Ryzen 9 7950X: Enhanced",sensor_id="0xf0000501",instance="0",reading_id="0x1000009",label="CPU CCD2 (Tdie)"} 33.375
+}
+
+func TestExporter(t *testing.T) {
+	exporter := newTestExporter(newTestReader(t, true))
+	exporter.Statistics = true
+
+	expected := `# HELP hwinfo_up Whether HWiNFO is active and its readings could be read.
+# TYPE hwinfo_up gauge
+hwinfo_up 1
+# HELP hwinfo_last_update_age_seconds Time since HWiNFO last updated the readings.
+# TYPE hwinfo_last_update_age_seconds gauge
+hwinfo_last_update_age_seconds 1.5
+# HELP hwinfo_temp_celsius HWiNFO temp readings in °C.
+# TYPE hwinfo_temp_celsius gauge
+hwinfo_temp_celsius{sensor="CPU [#0]: AMD Ryzen 9 7950X: Enhanced",sensor_id="0xf0000300",instance="0",reading_id="0x1000000",label="CPU (Tctl/Tdie)"} 47.25
+# HELP hwinfo_temp_celsius_min Minimum of HWiNFO temp readings in °C.
+# TYPE hwinfo_temp_celsius_min gauge
+hwinfo_temp_celsius_min{sensor="CPU [#0]: AMD Ryzen 9 7950X: Enhanced",sensor_id="0xf0000300",instance="0",reading_id="0x1000000",label="CPU (Tctl/Tdie)"} 40
+# HELP hwinfo_temp_celsius_max Maximum of HWiNFO temp readings in °C.
+# TYPE hwinfo_temp_celsius_max gauge
+hwinfo_temp_celsius_max{sensor="CPU [#0]: AMD Ryzen 9 7950X: Enhanced",sensor_id="0xf0000300",instance="0",reading_id="0x1000000",label="CPU (Tctl/Tdie)"} 80.5
+# HELP hwinfo_temp_celsius_avg Average of HWiNFO temp readings in °C.
+# TYPE hwinfo_temp_celsius_avg gauge
+hwinfo_temp_celsius_avg{sensor="CPU [#0]: AMD Ryzen 9 7950X: Enhanced",sensor_id="0xf0000300",instance="0",reading_id="0x1000000",label="CPU (Tctl/Tdie)"} 50
+# HELP hwinfo_clock_hertz HWiNFO clock readings in Hz.
+# TYPE hwinfo_clock_hertz gauge
+hwinfo_clock_hertz{sensor="CPU [#0]: AMD Ryzen 9 7950X: Enhanced",sensor_id="0xf0000300",instance="0",reading_id="0x6000000",label="Core 0 Clock"} 4.2e+09
+# HELP hwinfo_clock_hertz_min Minimum of HWiNFO clock readings in Hz.
+# TYPE hwinfo_clock_hertz_min gauge
+hwinfo_clock_hertz_min{sensor="CPU [#0]: AMD Ryzen 9 7950X: Enhanced",sensor_id="0xf0000300",instance="0",reading_id="0x6000000",label="Core 0 Clock"} 3e+09
+# HELP hwinfo_clock_hertz_max Maximum of HWiNFO clock readings in Hz.
+# TYPE hwinfo_clock_hertz_max gauge
+hwinfo_clock_hertz_max{sensor="CPU [#0]: AMD Ryzen 9 7950X: Enhanced",sensor_id="0xf0000300",instance="0",reading_id="0x6000000",label="Core 0 Clock"} 5.5e+09
+# HELP hwinfo_clock_hertz_avg Average of HWiNFO clock readings in Hz.
+# TYPE hwinfo_clock_hertz_avg gauge
+hwinfo_clock_hertz_avg{sensor="CPU [#0]: AMD Ryzen 9 7950X: Enhanced",sensor_id="0xf0000300",instance="0",reading_id="0x6000000",label="Core 0 Clock"} 4.5e+09
+# HELP hwinfo_other HWiNFO other readings.
+# TYPE hwinfo_other gauge
+hwinfo_other{sensor="CPU [#0]: AMD Ryzen 9 7950X: Enhanced",sensor_id="0xf0000300",instance="0",reading_id="0x7000000",label="Thermal Throttling (HTC)"} 0
+# HELP hwinfo_other_min Minimum of HWiNFO other readings.
+# TYPE hwinfo_other_min gauge
+hwinfo_other_min{sensor="CPU [#0]: AMD Ryzen 9 7950X: Enhanced",sensor_id="0xf0000300",instance="0",reading_id="0x7000000",label="Thermal Throttling (HTC)"} 0
+# HELP hwinfo_other_max Maximum of HWiNFO other readings.
+# TYPE hwinfo_other_max gauge
+hwinfo_other_max{sensor="CPU [#0]: AMD Ryzen 9 7950X: Enhanced",sensor_id="0xf0000300",instance="0",reading_id="0x7000000",label="Thermal Throttling (HTC)"} 1
+# HELP hwinfo_other_avg Average of HWiNFO other readings.
+# TYPE hwinfo_other_avg gauge
+hwinfo_other_avg{sensor="CPU [#0]: AMD Ryzen 9 7950X: Enhanced",sensor_id="0xf0000300",instance="0",reading_id="0x7000000",label="Thermal Throttling (HTC)"} 0.5
+# HELP hwinfo_other_bytes HWiNFO other readings in B.
+# TYPE hwinfo_other_bytes gauge
+hwinfo_other_bytes{sensor="GPU \"Primary\"",sensor_id="0xe0002000",instance="1",reading_id="0x9000000",label="GPU Memory Allocated"} 2.147483648e+09
+# HELP hwinfo_other_bytes_min Minimum of HWiNFO other readings in B.
+# TYPE hwinfo_other_bytes_min gauge
+hwinfo_other_bytes_min{sensor="GPU \"Primary\"",sensor_id="0xe0002000",instance="1",reading_id="0x9000000",label="GPU Memory Allocated"} 1.073741824e+09
+# HELP hwinfo_other_bytes_max Maximum of HWiNFO other readings in B.
+# TYPE hwinfo_other_bytes_max gauge
+hwinfo_other_bytes_max{sensor="GPU \"Primary\"",sensor_id="0xe0002000",instance="1",reading_id="0x9000000",label="GPU Memory Allocated"} 4.294967296e+09
+# HELP hwinfo_other_bytes_avg Average of HWiNFO other readings in B.
+# TYPE hwinfo_other_bytes_avg gauge
+hwinfo_other_bytes_avg{sensor="GPU \"Primary\"",sensor_id="0xe0002000",instance="1",reading_id="0x9000000",label="GPU Memory Allocated"} 3.221225472e+09
+`
+
+	if actual := scrape(t, exporter); actual != expected {
+		t.Errorf("unexpected metrics:\n%s", actual)
+	}
+}
+
+func TestExporterInactive(t *testing.T) {
+	exporter := newTestExporter(newTestReader(t, false))
+	exporter.Namespace = "pc"
+
+	metrics := scrape(t, exporter)
+	if !strings.Contains(metrics, "\npc_up 0\n") || !strings.Contains(metrics, "\npc_temp_celsius{") {
+		t.Errorf("expected pc_up 0 and the last readings, got:\n%s", metrics)
+	}
+}
+
+// failingSource fails to read the header like a Supervisor that is disconnected.
+type failingSource struct {
+	hwinfoshmem.Source
+}
+
+func (failingSource) GetHeader() (*hwinfoshmem.HwinfoHeader, error) {
+	return nil, hwinfoshmem.ErrDisconnected
+}
+
+func TestExporterUnreadable(t *testing.T) {
+	metrics := scrape(t, newTestExporter(failingSource{}))
+
+	expected := "# HELP hwinfo_up Whether HWiNFO is active and its readings could be read.\n# TYPE hwinfo_up gauge\nhwinfo_up 0\n"
+	if metrics != expected {
+		t.Errorf("expected only hwinfo_up, got:\n%s", metrics)
+	}
+}
+
+func TestExporterNaN(t *testing.T) {
+	reader := newTestReader(t, true)
+	info, err := reader.GetHeader()
+	if err != nil {
+		t.Fatalf("failed to get header: %v", err)
+	}
+
+	readings, err := reader.GetReadings(info)
+	if err != nil {
+		t.Fatalf("failed to get readings: %v", err)
+	}
+	readings[0].Value = hwinfoshmem.NewHwinfoFloat64(math.NaN())
+
+	exporter := newTestExporter(reader)
+	exporter.Selector = selector.MustCompile("id==0x1000000 && instance==0")
+	if metrics := scrape(t, exporter); !strings.Contains(metrics, `label="CPU (Tctl/Tdie)"} NaN`) {
+		t.Errorf("expected NaN value, got:\n%s", metrics)
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, io.ErrClosedPipe
+}
+
+func TestExporterWriteError(t *testing.T) {
+	if err := newTestExporter(newTestReader(t, true)).Write(failingWriter{}); !errors.Is(err, io.ErrClosedPipe) {
+		t.Errorf("expected io.ErrClosedPipe, got %v", err)
+	}
+}
+
+func TestExporterServeHTTP(t *testing.T) {
+	server := httptest.NewServer(newTestExporter(newTestReader(t, true)))
+	defer server.Close()
+
+	response, err := http.Get(server.URL + "/metrics")
+	if err != nil {
+		t.Fatalf("failed to scrape: %v", err)
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		t.Fatalf("failed to read response: %v", err)
+	}
+
+	if response.StatusCode != http.StatusOK || response.Header.Get("Content-Type") != prometheus.ContentType {
+		t.Errorf("unexpected status %d and content type %q", response.StatusCode, response.Header.Get("Content-Type"))
+	}
+
+	if !strings.Contains(string(body), "\nhwinfo_up 1\n") {
+		t.Errorf("expected hwinfo_up 1, got:\n%s", body)
+	}
+}
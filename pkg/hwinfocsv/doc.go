@@ -0,0 +1,23 @@
+/*
+Package hwinfocsv reads the CSV sensor logs written by HWiNFO into [hwinfoshmem.Snapshot] values,
+the same model used for HWiNFO's shared memory. This allows analyzing historical logs and live data
+alike.
+
+A log looks as follows, where the last two rows, the footer, are written when HWiNFO stops logging:
+
+	"Date","Time","Core VIDs (avg) [V]","CPU (Tctl/Tdie) [°C]","Thermal Throttling (HTC) [Yes/No]",
+	17.9.2023,15:56:40.123,1.234,45.5,No,
+	...
+	"Date","Time","Core VIDs (avg) [V]","CPU (Tctl/Tdie) [°C]","Thermal Throttling (HTC) [Yes/No]",
+	"","","CPU [#0]: AMD Ryzen 9 7950X","CPU [#0]: AMD Ryzen 9 7950X: Enhanced","CPU [#0]: AMD Ryzen 9 7950X: Enhanced",
+
+The header contains the label of each reading followed by its unit between square brackets. The
+footer repeats the header and contains the name of the sensor each reading belongs to.
+
+HWiNFO writes the log using the codepage of the system, see [bytesutil.Codepage]. Logs starting
+with a UTF-8 byte order mark are read as UTF-8 regardless of the codepage.
+
+Logs are read using [Reader] and written using [Writer], or [Logger] to split them into multiple
+files by size or time.
+*/
+package hwinfocsv
@@ -0,0 +1,143 @@
+package hwinfocsv
+
+import (
+	"errors"
+	"fmt"
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/hwinfoshmem"
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/selector"
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/util/bytesutil"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Logger writes snapshots to log files, starting a new log when the current one becomes too large
+// or spans too much time. Every log has its own header and footer so that it can be opened on its
+// own.
+//
+// The name of a log is the name of [Logger.Path] with the LastUpdate of its first row inserted
+// before the extension, e.g. sensors-20230917-155640.csv for sensors.csv.
+//
+// Logger has an initializer function, [NewLogger].
+type Logger struct {
+	// Path of the logs, see [Logger] for how it is used to name them.
+	Path string
+
+	// Selector selects the readings to log. When nil, all readings are logged.
+	Selector *selector.Selector
+
+	// Location used to format the date and time of rows and names of logs.
+	// Defaults to [time.Local].
+	Location *time.Location
+
+	// MaxSize is the size in bytes after which a new log is started. A log exceeds it by at most
+	// one row and the footer. Zero means unlimited.
+	MaxSize int64
+
+	// MaxDuration is the time between the first row of a log and the row that starts a new log,
+	// based on the LastUpdate of the snapshots. Zero means unlimited.
+	MaxDuration time.Duration
+
+	codepage bytesutil.Codepage
+
+	file   *os.File
+	writer *Writer
+	start  time.Time
+}
+
+// NewLogger creates a logger that writes the logs using the codepage.
+func NewLogger(path string, codepage bytesutil.Codepage) *Logger {
+	return &Logger{
+		Path:     path,
+		Location: time.Local,
+		codepage: codepage,
+	}
+}
+
+// Write writes the snapshot to the current log, starting a new log first when the current one
+// spans [Logger.MaxDuration]. When the log reaches [Logger.MaxSize] afterward, it is closed.
+func (logger *Logger) Write(snapshot *hwinfoshmem.Snapshot) error {
+	if logger.writer != nil && logger.MaxDuration > 0 && snapshot.LastUpdate.Sub(logger.start) >= logger.MaxDuration {
+		if err := logger.Close(); err != nil {
+			return err
+		}
+	}
+
+	if logger.writer == nil {
+		if err := logger.open(snapshot.LastUpdate); err != nil {
+			return err
+		}
+	}
+
+	if err := logger.writer.Write(snapshot); err != nil {
+		return err
+	}
+
+	if logger.MaxSize > 0 && logger.writer.Size() >= logger.MaxSize {
+		return logger.Close()
+	}
+
+	return nil
+}
+
+// CurrentPath returns the path of the log that is being written, or an empty string when no log
+// is open.
+func (logger *Logger) CurrentPath() string {
+	if logger.file == nil {
+		return ""
+	}
+
+	return logger.file.Name()
+}
+
+// Close writes the footer and closes the current log. The next snapshot written starts a new log.
+func (logger *Logger) Close() error {
+	if logger.file == nil {
+		return nil
+	}
+
+	err := logger.writer.Close()
+	if closeErr := logger.file.Close(); err == nil && closeErr != nil {
+		err = fmt.Errorf("error closing log: %w", closeErr)
+	}
+
+	logger.file = nil
+	logger.writer = nil
+
+	return err
+}
+
+// open creates a new log named after the time of its first row.
+// When a log with that name exists, a counter is appended.
+func (logger *Logger) open(start time.Time) error {
+	location := logger.Location
+	if location == nil {
+		location = time.Local
+	}
+
+	extension := filepath.Ext(logger.Path)
+	base := strings.TrimSuffix(logger.Path, extension) + "-" + start.In(location).Format("20060102-150405")
+
+	path := base + extension
+	for i := 1; ; i++ {
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o666)
+		if errors.Is(err, fs.ErrExist) {
+			path = fmt.Sprintf("%s-%d%s", base, i, extension)
+			continue
+		} else if err != nil {
+			return fmt.Errorf("error creating log: %w", err)
+		}
+
+		logger.file = file
+		break
+	}
+
+	logger.writer = NewWriter(logger.file, logger.codepage)
+	logger.writer.Selector = logger.Selector
+	logger.writer.Location = location
+	logger.start = start
+
+	return nil
+}
@@ -0,0 +1,528 @@
+package hwinfocsv
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/hwinfoshmem"
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/units"
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/util/bytesutil"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidLog is returned when reading a file that is not a HWiNFO CSV log.
+var ErrInvalidLog = errors.New("invalid HWiNFO CSV log")
+
+// utf8ByteOrderMark starts logs that are encoded using UTF-8.
+var utf8ByteOrderMark = []byte{0xef, 0xbb, 0xbf}
+
+// dateTimeLayouts are the formats of the date and time columns written by HWiNFO in different
+// locales. The time can contain milliseconds, which time.Parse accepts without them being part of
+// the layout.
+// Dates such as 12/9/2023 match both month/day and day/month, see [Reader.detectLayout].
+var dateTimeLayouts = []string{
+	"2.1.2006 15:04:05",
+	"2006-01-02 15:04:05",
+	"1/2/2006 15:04:05",
+	"2/1/2006 15:04:05",
+	"2006/1/2 15:04:05",
+}
+
+// column is a reading in the log.
+type column struct {
+	// index of the column in the rows.
+	index int
+
+	sensor      int
+	readingType hwinfoshmem.ReadingType
+	id          uint32
+	label       string
+	unit        string
+
+	minimum float64
+	maximum float64
+	sum     float64
+	count   uint64
+}
+
+// Reader reads the snapshots of a HWiNFO CSV log, one for each row.
+//
+// The log does not contain the ids of sensors and readings. Sensors get SensorId 0 and their
+// position as SensorInstance, readings get their position within the sensor as Id.
+// The type of readings is derived from their unit, see [units.Parse].
+// When the log does not contain a footer, e.g. because HWiNFO was still logging, all readings
+// belong to a single sensor without a name.
+//
+// ValueMin, ValueMax, and ValueAvg are calculated from the rows read so far, like HWiNFO does since
+// it started logging. Empty and invalid values are NaN and do not affect them.
+//
+// Reader has an initializer function, [NewReader].
+type Reader struct {
+	// Location used to interpret the date and time of rows. Defaults to [time.Local].
+	Location *time.Location
+
+	// DateTimeLayout is the format of the date and time columns separated by a space, see
+	// [time.Layout]. When empty, the layout is detected from the first row. When the date of the
+	// first row matches several layouts, e.g. 12/9/2023, the rows are read ahead until a date
+	// matches only one of them. When no date does, Read returns an error after which the layout
+	// can be set and Read called again.
+	DateTimeLayout string
+
+	csv     *csv.Reader
+	header  []string
+	sensors []string
+	columns []*column
+
+	// pending contains the rows that have been read ahead to detect the layout.
+	pending [][]string
+
+	// done is set once the footer or the end of the log has been read.
+	done bool
+
+	previousUpdate time.Time
+	pollingPeriod  time.Duration
+}
+
+// NewReader reads the header and footer of the log, which is encoded using the codepage.
+//
+// The footer is read by seeking to the end when reader implements [io.ReadSeeker]. Otherwise,
+// the log is read into memory entirely.
+func NewReader(reader io.Reader, codepage bytesutil.Codepage) (*Reader, error) {
+	seeker, ok := reader.(io.ReadSeeker)
+	if !ok {
+		data, err := io.ReadAll(reader)
+		if err != nil {
+			return nil, fmt.Errorf("error reading log: %w", err)
+		}
+		seeker = bytes.NewReader(data)
+	}
+
+	buffered := bufio.NewReader(seeker)
+	if start, err := buffered.Peek(len(utf8ByteOrderMark)); err == nil && bytes.Equal(start, utf8ByteOrderMark) {
+		codepage = bytesutil.Codepage65001
+		_, _ = buffered.Discard(len(utf8ByteOrderMark))
+	}
+
+	headerLine, err := buffered.ReadBytes('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("error reading log header: %w", err)
+	}
+	headerLine = bytes.TrimRight(headerLine, "\r\n")
+
+	headerReader, err := newCsvReader(bytes.NewReader(headerLine), codepage)
+	if err != nil {
+		return nil, err
+	}
+
+	header, err := headerReader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidLog, err)
+	}
+
+	if len(header) < 2 || !strings.EqualFold(header[0], "Date") || !strings.EqualFold(header[1], "Time") {
+		return nil, fmt.Errorf("%w: expected Date and Time columns", ErrInvalidLog)
+	}
+
+	sensors, err := readFooter(seeker, headerLine, codepage)
+	if err != nil {
+		return nil, err
+	}
+
+	// The rows are read after the header line which has been consumed from buffered.
+	rows, err := newCsvReader(buffered, codepage)
+	if err != nil {
+		return nil, err
+	}
+
+	csvReader := &Reader{
+		Location: time.Local,
+		csv:      rows,
+	}
+	csvReader.setColumns(header, sensors)
+
+	return csvReader, nil
+}
+
+func newCsvReader(reader io.Reader, codepage bytesutil.Codepage) (*csv.Reader, error) {
+	decoded, err := bytesutil.NewAnsiReader(reader, codepage)
+	if err != nil {
+		return nil, err
+	}
+
+	csvReader := csv.NewReader(decoded)
+	csvReader.FieldsPerRecord = -1
+	csvReader.LazyQuotes = true
+	csvReader.ReuseRecord = true
+
+	return csvReader, nil
+}
+
+// readFooter returns the sensor name of each column, or nil when the log does not have a footer.
+// The footer is found by looking for the last repetition of the header line in the tail of the log.
+// Afterward, the position is restored to just after the header.
+func readFooter(seeker io.ReadSeeker, headerLine []byte, codepage bytesutil.Codepage) ([]string, error) {
+	position, err := seeker.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil, fmt.Errorf("error reading log footer: %w", err)
+	}
+
+	size, err := seeker.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, fmt.Errorf("error reading log footer: %w", err)
+	}
+
+	// The sensor names are usually longer than the labels, allow for plenty.
+	tailSize := min(size, int64(16*len(headerLine)+64*1024))
+	tail := make([]byte, tailSize)
+	if _, err = seeker.Seek(size-tailSize, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("error reading log footer: %w", err)
+	}
+	if _, err = io.ReadFull(seeker, tail); err != nil {
+		return nil, fmt.Errorf("error reading log footer: %w", err)
+	}
+
+	if _, err = seeker.Seek(position, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("error reading log footer: %w", err)
+	}
+
+	start := bytes.LastIndex(tail, append([]byte{'\n'}, headerLine...))
+	if start < 0 {
+		return nil, nil
+	}
+
+	footer, err := newCsvReader(bytes.NewReader(tail[start+1:]), codepage)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = footer.Read(); err != nil {
+		return nil, nil
+	}
+
+	sensors, err := footer.Read()
+	if err != nil {
+		return nil, nil
+	}
+
+	return slices.Clone(sensors), nil
+}
+
+// setColumns creates the columns for all readings in the header.
+func (reader *Reader) setColumns(header []string, sensors []string) {
+	reader.header = slices.Clone(header)
+	reader.sensors = make([]string, 0)
+	sensorIndices := make(map[string]int)
+	readingCounts := make([]uint32, 0)
+
+	for i := 2; i < len(header); i++ {
+		if header[i] == "" {
+			// HWiNFO ends every row with a comma.
+			continue
+		}
+
+		sensorName := ""
+		if i < len(sensors) {
+			sensorName = sensors[i]
+		}
+
+		sensorIndex, ok := sensorIndices[sensorName]
+		if !ok {
+			sensorIndex = len(reader.sensors)
+			sensorIndices[sensorName] = sensorIndex
+			reader.sensors = append(reader.sensors, sensorName)
+			readingCounts = append(readingCounts, 0)
+		}
+
+		label, unit := splitLabel(header[i])
+		reader.columns = append(reader.columns, &column{
+			index:       i,
+			sensor:      sensorIndex,
+			readingType: readingTypeOf(unit),
+			id:          readingCounts[sensorIndex],
+			label:       label,
+			unit:        unit,
+		})
+		readingCounts[sensorIndex]++
+	}
+}
+
+// splitLabel splits a header such as "CPU (Tctl/Tdie) [°C]" into the label and unit.
+func splitLabel(header string) (string, string) {
+	if !strings.HasSuffix(header, "]") {
+		return header, ""
+	}
+
+	start := strings.LastIndex(header, " [")
+	if start < 0 {
+		return header, ""
+	}
+
+	return header[:start], header[start+2 : len(header)-1]
+}
+
+// readingTypeOf derives the type of a reading from its unit.
+func readingTypeOf(unit string) hwinfoshmem.ReadingType {
+	switch units.Parse(unit).Dimension {
+	case units.DimensionTemperature:
+		return hwinfoshmem.SENSOR_TYPE_TEMP
+	case units.DimensionVoltage:
+		return hwinfoshmem.SENSOR_TYPE_VOLT
+	case units.DimensionRotationSpeed:
+		return hwinfoshmem.SENSOR_TYPE_FAN
+	case units.DimensionCurrent:
+		return hwinfoshmem.SENSOR_TYPE_CURRENT
+	case units.DimensionPower:
+		return hwinfoshmem.SENSOR_TYPE_POWER
+	case units.DimensionFrequency:
+		if strings.HasSuffix(unit, "Hz") {
+			return hwinfoshmem.SENSOR_TYPE_CLOCK
+		}
+	case units.DimensionRatio:
+		return hwinfoshmem.SENSOR_TYPE_USAGE
+	}
+
+	return hwinfoshmem.SENSOR_TYPE_OTHER
+}
+
+// booleanValues are the values of the words used by readings with a boolean unit such as Yes/No.
+var booleanValues = map[string]float64{
+	"yes":   1,
+	"no":    0,
+	"true":  1,
+	"false": 0,
+	"on":    1,
+	"off":   0,
+}
+
+// parseValue parses a value of a row. Yes and No are 1 and 0, empty and invalid values are NaN.
+func parseValue(text string) float64 {
+	text = strings.TrimSpace(text)
+
+	if value, ok := booleanValues[strings.ToLower(text)]; ok {
+		return value
+	}
+
+	if strings.Contains(text, ",") && !strings.Contains(text, ".") {
+		text = strings.Replace(text, ",", ".", 1)
+	}
+
+	value, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return math.NaN()
+	}
+
+	return value
+}
+
+// Read returns the snapshot of the next row, or io.EOF after the last row.
+func (reader *Reader) Read() (*hwinfoshmem.Snapshot, error) {
+	row, err := reader.readRow()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(row) < 2 {
+		return nil, fmt.Errorf("%w: row without date and time", ErrInvalidLog)
+	}
+
+	if reader.DateTimeLayout == "" {
+		// Reading ahead reuses the record of the row.
+		row = slices.Clone(row)
+		if err = reader.detectLayout(row); err != nil {
+			return nil, err
+		}
+	}
+
+	lastUpdate, err := reader.parseTime(row[0] + " " + row[1])
+	if err != nil {
+		return nil, err
+	}
+
+	if !reader.previousUpdate.IsZero() && lastUpdate.After(reader.previousUpdate) {
+		reader.pollingPeriod = lastUpdate.Sub(reader.previousUpdate)
+	}
+	reader.previousUpdate = lastUpdate
+
+	snapshot := &hwinfoshmem.Snapshot{
+		Active:        true,
+		Status:        "HWiS",
+		Version:       2,
+		Revision:      1,
+		LastUpdate:    lastUpdate,
+		PollingPeriod: reader.pollingPeriod,
+		Sensors:       make([]*hwinfoshmem.Sensor, len(reader.sensors)),
+		Readings:      make([]*hwinfoshmem.Reading, len(reader.columns)),
+	}
+
+	for i, name := range reader.sensors {
+		snapshot.Sensors[i] = &hwinfoshmem.Sensor{
+			Index:              uint32(i),
+			SensorInstance:     uint32(i),
+			SensorNameOriginal: name,
+			SensorName:         name,
+			Readings:           make([]*hwinfoshmem.Reading, 0),
+		}
+	}
+
+	for i, column := range reader.columns {
+		value := math.NaN()
+		if column.index < len(row) {
+			value = parseValue(row[column.index])
+		}
+
+		if !math.IsNaN(value) {
+			if column.count == 0 || value < column.minimum {
+				column.minimum = value
+			}
+			if column.count == 0 || value > column.maximum {
+				column.maximum = value
+			}
+			column.sum += value
+			column.count++
+		}
+
+		reading := &hwinfoshmem.Reading{
+			Sensor:        snapshot.Sensors[column.sensor],
+			Type:          column.readingType,
+			SensorIndex:   uint32(column.sensor),
+			Id:            column.id,
+			OriginalLabel: column.label,
+			UserLabel:     column.label,
+			Unit:          column.unit,
+			Value:         value,
+			ValueMin:      math.NaN(),
+			ValueMax:      math.NaN(),
+			ValueAvg:      math.NaN(),
+		}
+
+		if column.count > 0 {
+			reading.ValueMin = column.minimum
+			reading.ValueMax = column.maximum
+			reading.ValueAvg = column.sum / float64(column.count)
+		}
+
+		reading.Sensor.Readings = append(reading.Sensor.Readings, reading)
+		snapshot.Readings[i] = reading
+	}
+
+	return snapshot, nil
+}
+
+// readRow returns the next row, first those that have been read ahead, or io.EOF after the last
+// row.
+func (reader *Reader) readRow() ([]string, error) {
+	if len(reader.pending) > 0 {
+		row := reader.pending[0]
+		reader.pending = reader.pending[1:]
+		return row, nil
+	}
+
+	return reader.readCsvRow()
+}
+
+// readCsvRow returns the next row of the csv reader, or io.EOF after the last row.
+func (reader *Reader) readCsvRow() ([]string, error) {
+	if reader.done {
+		return nil, io.EOF
+	}
+
+	row, err := reader.csv.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			reader.done = true
+			return nil, io.EOF
+		}
+		return nil, fmt.Errorf("%w: %w", ErrInvalidLog, err)
+	}
+
+	if slices.Equal(row, reader.header) {
+		// The footer marks the end of the log.
+		reader.done = true
+		return nil, io.EOF
+	}
+
+	return row, nil
+}
+
+// detectLayout sets DateTimeLayout to the layout of the date and time of the row.
+// When several layouts match, the following rows are read ahead until only one of them matches.
+// The row must not be reused by the csv reader.
+func (reader *Reader) detectLayout(row []string) error {
+	text := row[0] + " " + row[1]
+	candidates := matchingLayouts(dateTimeLayouts, text, reader.location())
+	if len(candidates) == 0 {
+		return fmt.Errorf("%w: unknown date and time format %q", ErrInvalidLog, text)
+	}
+
+	for ahead := 0; len(candidates) > 1; ahead++ {
+		if ahead == len(reader.pending) {
+			next, err := reader.readCsvRow()
+			if err != nil {
+				// Keep the row so Read can be called again after setting DateTimeLayout.
+				reader.pending = append([][]string{row}, reader.pending...)
+
+				if errors.Is(err, io.EOF) {
+					return fmt.Errorf(
+						"%w: ambiguous date and time format %q, set DateTimeLayout",
+						ErrInvalidLog,
+						text,
+					)
+				}
+				return err
+			}
+
+			reader.pending = append(reader.pending, slices.Clone(next))
+		}
+
+		next := reader.pending[ahead]
+		if len(next) < 2 {
+			continue
+		}
+
+		// An invalid row does not rule out any layout, it fails once it is read.
+		if matching := matchingLayouts(candidates, next[0]+" "+next[1], reader.location()); len(matching) > 0 {
+			candidates = matching
+		}
+	}
+
+	reader.DateTimeLayout = candidates[0]
+
+	return nil
+}
+
+// matchingLayouts returns the layouts that can parse the text.
+func matchingLayouts(layouts []string, text string, location *time.Location) []string {
+	matching := make([]string, 0, len(layouts))
+	for _, layout := range layouts {
+		if _, err := time.ParseInLocation(layout, text, location); err == nil {
+			matching = append(matching, layout)
+		}
+	}
+
+	return matching
+}
+
+func (reader *Reader) location() *time.Location {
+	if reader.Location == nil {
+		return time.Local
+	}
+
+	return reader.Location
+}
+
+// parseTime parses the date and time of a row using DateTimeLayout.
+func (reader *Reader) parseTime(text string) (time.Time, error) {
+	parsed, err := time.ParseInLocation(reader.DateTimeLayout, text, reader.location())
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: invalid date and time %q", ErrInvalidLog, text)
+	}
+
+	return parsed, nil
+}
@@ -0,0 +1,258 @@
+package hwinfocsv_test
+
+import (
+	"bytes"
+	_ "embed"
+	"errors"
+	"fmt"
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/hwinfocsv"
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/hwinfoshmem"
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/util/bytesutil"
+	"io"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+// sensorsLog is a log of three rows encoded using codepage 1252, including the footer.
+//
+//go:embed testdata/sensors.csv
+var sensorsLog []byte
+
+func readAll(t *testing.T, reader *hwinfocsv.Reader) []*hwinfoshmem.Snapshot {
+	t.Helper()
+
+	snapshots := make([]*hwinfoshmem.Snapshot, 0)
+	for {
+		snapshot, err := reader.Read()
+		if err == io.EOF {
+			return snapshots
+		} else if err != nil {
+			t.Fatalf("failed to read row %d: %v", len(snapshots), err)
+		}
+
+		snapshots = append(snapshots, snapshot)
+	}
+}
+
+func ExampleReader() {
+	reader, err := hwinfocsv.NewReader(bytes.NewReader(sensorsLog), bytesutil.Codepage1252)
+	if err != nil {
+		fmt.Printf("Failed to open log: %s\n", err)
+		return
+	}
+	reader.Location = time.UTC
+
+	for {
+		snapshot, err := reader.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			fmt.Printf("Failed to read log: %s\n", err)
+			return
+		}
+
+		reading := snapshot.Readings[1]
+		fmt.Printf(
+			"%s %s: %s: %.1f %s\n",
+			snapshot.LastUpdate.Format(time.TimeOnly),
+			reading.Sensor.SensorName,
+			reading.UserLabel,
+			reading.Value,
+			reading.Unit,
+		)
+	}
+
+	// Output:
+	// 15:56:40 CPU [#0]: AMD Ryzen 9 7950X: Enhanced: CPU (Tctl/Tdie): 45.5 °C
+	// 15:56:42 CPU [#0]: AMD Ryzen 9 7950X: Enhanced: CPU (Tctl/Tdie): 47.5 °C
+	// 15:56:44 CPU [#0]: AMD Ryzen 9 7950X: Enhanced: CPU (Tctl/Tdie): 46.0 °C
+}
+
+func TestReader(t *testing.T) {
+	reader, err := hwinfocsv.NewReader(bytes.NewReader(sensorsLog), bytesutil.Codepage1252)
+	if err != nil {
+		t.Fatalf("failed to open log: %v", err)
+	}
+	reader.Location = time.UTC
+
+	snapshots := readAll(t, reader)
+	if len(snapshots) != 3 {
+		t.Fatalf("expected 3 snapshots, got %d", len(snapshots))
+	}
+
+	last := snapshots[2]
+	if expected := time.Date(2023, 9, 17, 15, 56, 44, 121e6, time.UTC); !last.LastUpdate.Equal(expected) {
+		t.Errorf("expected last update %s, got %s", expected, last.LastUpdate)
+	}
+
+	if last.PollingPeriod != 1996*time.Millisecond || !last.Active {
+		t.Errorf("expected an active snapshot with polling period 1996ms, got %+v", last)
+	}
+
+	expectedSensors := []struct {
+		name     string
+		readings int
+	}{
+		{"CPU [#0]: AMD Ryzen 9 7950X", 2},
+		{"CPU [#0]: AMD Ryzen 9 7950X: Enhanced", 2},
+		{"GPU [#0]: NVIDIA GeForce RTX 4090", 2},
+	}
+	if len(last.Sensors) != len(expectedSensors) {
+		t.Fatalf("expected %d sensors, got %d", len(expectedSensors), len(last.Sensors))
+	}
+
+	for i, expected := range expectedSensors {
+		sensor := last.Sensors[i]
+		if sensor.SensorName != expected.name || len(sensor.Readings) != expected.readings || sensor.SensorInstance != uint32(i) {
+			t.Errorf("expected sensor %d to be %q with %d readings, got %q with %d", i, expected.name, expected.readings, sensor.SensorName, len(sensor.Readings))
+		}
+	}
+
+	expectedReadings := []struct {
+		sensor      int
+		id          uint32
+		readingType hwinfoshmem.ReadingType
+		label       string
+		unit        string
+		value       float64
+		minimum     float64
+		maximum     float64
+		average     float64
+	}{
+		{0, 0, hwinfoshmem.SENSOR_TYPE_VOLT, "Core VIDs (avg)", "V", 1.21, 1.21, 1.25, (1.234 + 1.25 + 1.21) / 3},
+		{1, 0, hwinfoshmem.SENSOR_TYPE_TEMP, "CPU (Tctl/Tdie)", "°C", 46, 45.5, 47.5, (45.5 + 47.5 + 46) / 3},
+		{1, 1, hwinfoshmem.SENSOR_TYPE_OTHER, "Thermal Throttling (HTC)", "Yes/No", 0, 0, 1, 1.0 / 3},
+		{0, 1, hwinfoshmem.SENSOR_TYPE_CLOCK, "Core 0 Clock (perf #1)", "MHz", 4300, 4200, 4500, 4333.333333333333},
+		{2, 0, hwinfoshmem.SENSOR_TYPE_FAN, "GPU Fan", "RPM", 1300, 1200, 1300, 1250},
+		{2, 1, hwinfoshmem.SENSOR_TYPE_OTHER, "Framerate", "FPS", 120, 120, 144, 132},
+	}
+	if len(last.Readings) != len(expectedReadings) {
+		t.Fatalf("expected %d readings, got %d", len(expectedReadings), len(last.Readings))
+	}
+
+	for i, expected := range expectedReadings {
+		reading := last.Readings[i]
+		if reading.Sensor != last.Sensors[expected.sensor] || reading.SensorIndex != uint32(expected.sensor) ||
+			reading.Id != expected.id || reading.Type != expected.readingType ||
+			reading.UserLabel != expected.label || reading.OriginalLabel != expected.label || reading.Unit != expected.unit {
+			t.Errorf("reading %d differs, expected %+v, got %+v", i, expected, reading)
+		}
+
+		for _, values := range [][2]float64{
+			{expected.value, reading.Value},
+			{expected.minimum, reading.ValueMin},
+			{expected.maximum, reading.ValueMax},
+			{expected.average, reading.ValueAvg},
+		} {
+			if math.Abs(values[0]-values[1]) > 1e-9 {
+				t.Errorf("value of reading %d differs, expected %v, got %v", i, values[0], values[1])
+			}
+		}
+	}
+
+	if framerate := snapshots[1].Readings[5]; !math.IsNaN(framerate.Value) || framerate.ValueMax != 144 {
+		t.Errorf("expected empty value to be NaN and to be ignored, got %+v", framerate)
+	}
+}
+
+func TestReaderWithoutFooter(t *testing.T) {
+	log := strings.Join([]string{
+		"\xef\xbb\xbf\"Date\",\"Time\",\"CPU Package Power [W]\",\"Vcore [V]\",",
+		"2023-09-17,15:56:40.000,\"65,5\",\"1,25\",",
+		"2023-09-17,15:56:41.000,\"70,0\",\"1,30\",",
+		"2023-09-17,15:56:42.000,",
+	}, "\n")
+
+	// A non-seekable reader, like a pipe.
+	reader, err := hwinfocsv.NewReader(io.MultiReader(strings.NewReader(log)), bytesutil.Codepage1252)
+	if err != nil {
+		t.Fatalf("failed to open log: %v", err)
+	}
+
+	snapshots := readAll(t, reader)
+	if len(snapshots) != 3 {
+		t.Fatalf("expected 3 snapshots, got %d", len(snapshots))
+	}
+
+	if reader.DateTimeLayout != "2006-01-02 15:04:05" {
+		t.Errorf("expected the layout to be detected, got %q", reader.DateTimeLayout)
+	}
+
+	second := snapshots[1]
+	if len(second.Sensors) != 1 || second.Sensors[0].SensorName != "" || len(second.Sensors[0].Readings) != 2 {
+		t.Fatalf("expected a single unnamed sensor, got %+v", second.Sensors)
+	}
+
+	if second.Readings[0].Type != hwinfoshmem.SENSOR_TYPE_POWER || second.Readings[0].Value != 70 ||
+		second.Readings[1].Value != 1.3 || second.Readings[1].Id != 1 {
+		t.Errorf("unexpected readings %+v and %+v", second.Readings[0], second.Readings[1])
+	}
+
+	if last := snapshots[2]; !math.IsNaN(last.Readings[0].Value) || last.Readings[0].ValueAvg != 67.75 {
+		t.Errorf("expected missing values to be NaN, got %+v", last.Readings[0])
+	}
+}
+
+func TestReaderDayMonth(t *testing.T) {
+	log := strings.Join([]string{
+		"Date,Time,Vcore [V],",
+		"12/9/2023,23:59:59.000,1.2,",
+		"13/9/2023,00:00:00.000,1.3,",
+	}, "\r\n")
+
+	reader, err := hwinfocsv.NewReader(strings.NewReader(log), bytesutil.Codepage1252)
+	if err != nil {
+		t.Fatalf("failed to open log: %v", err)
+	}
+	reader.Location = time.UTC
+
+	snapshots := readAll(t, reader)
+	if len(snapshots) != 2 {
+		t.Fatalf("expected 2 snapshots, got %d", len(snapshots))
+	}
+
+	if reader.DateTimeLayout != "2/1/2006 15:04:05" {
+		t.Errorf("expected the day/month layout, got %q", reader.DateTimeLayout)
+	}
+
+	expected := time.Date(2023, time.September, 12, 23, 59, 59, 0, time.UTC)
+	if !snapshots[0].LastUpdate.Equal(expected) || !snapshots[1].LastUpdate.Equal(expected.Add(time.Second)) {
+		t.Errorf("unexpected dates %s and %s", snapshots[0].LastUpdate, snapshots[1].LastUpdate)
+	}
+}
+
+func TestReaderAmbiguousDate(t *testing.T) {
+	log := "Date,Time,Vcore [V],\r\n12/9/2023,23:59:58.000,1.2,\r\n12/9/2023,23:59:59.000,1.3,\r\n"
+
+	reader, err := hwinfocsv.NewReader(strings.NewReader(log), bytesutil.Codepage1252)
+	if err != nil {
+		t.Fatalf("failed to open log: %v", err)
+	}
+
+	if _, err = reader.Read(); !errors.Is(err, hwinfocsv.ErrInvalidLog) {
+		t.Fatalf("expected ErrInvalidLog for ambiguous date, got %v", err)
+	}
+
+	reader.DateTimeLayout = "2/1/2006 15:04:05"
+	snapshots := readAll(t, reader)
+	if len(snapshots) != 2 || snapshots[0].LastUpdate.Month() != time.September {
+		t.Errorf("expected 2 snapshots in September, got %v", snapshots)
+	}
+}
+
+func TestInvalidLog(t *testing.T) {
+	if _, err := hwinfocsv.NewReader(strings.NewReader("a,b,c\n1,2,3\n"), bytesutil.Codepage1252); !errors.Is(err, hwinfocsv.ErrInvalidLog) {
+		t.Errorf("expected ErrInvalidLog, got %v", err)
+	}
+
+	reader, err := hwinfocsv.NewReader(strings.NewReader("Date,Time,Vcore [V]\nyesterday,noon,1.2\n"), bytesutil.Codepage1252)
+	if err != nil {
+		t.Fatalf("failed to open log: %v", err)
+	}
+
+	if _, err = reader.Read(); !errors.Is(err, hwinfocsv.ErrInvalidLog) {
+		t.Errorf("expected ErrInvalidLog for invalid date, got %v", err)
+	}
+}
@@ -0,0 +1,245 @@
+package hwinfocsv
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/hwinfoshmem"
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/selector"
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/units"
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/util/bytesutil"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultDateLayout and DefaultTimeLayout are the formats of the date and time columns used by
+// HWiNFO.
+const (
+	DefaultDateLayout = "2.1.2006"
+	DefaultTimeLayout = "15:04:05.000"
+)
+
+// Writer writes snapshots as rows of a log in the format of HWiNFO's CSV logging, see the package
+// documentation.
+//
+// The columns are determined by the first snapshot written. Readings are matched to the columns
+// using [hwinfoshmem.ReadingKey], readings that appear later are not logged and the cells of
+// readings that disappear are left empty.
+//
+// Writer has an initializer function, [NewWriter].
+type Writer struct {
+	// Selector selects the readings to log. When nil, all readings are logged.
+	Selector *selector.Selector
+
+	// Location used to format the date and time of rows. Defaults to [time.Local].
+	Location *time.Location
+
+	// DateLayout is the format of the date column, see [time.Layout].
+	DateLayout string
+
+	// TimeLayout is the format of the time column, see [time.Layout].
+	TimeLayout string
+
+	writer   io.Writer
+	codepage bytesutil.Codepage
+
+	// columns contains the key of the reading of each column after the date and time.
+	columns []hwinfoshmem.ReadingKey
+
+	// units contains the parsed unit of each column, used to write booleans as Yes or No.
+	units []units.Unit
+
+	// header and footer are the fields of the rows written by Close.
+	header []string
+	footer []string
+
+	size   int64
+	closed bool
+}
+
+// NewWriter creates a writer that writes the log to writer using the codepage.
+// When the codepage is UTF-8, the log starts with a byte order mark.
+func NewWriter(writer io.Writer, codepage bytesutil.Codepage) *Writer {
+	return &Writer{
+		Location:   time.Local,
+		DateLayout: DefaultDateLayout,
+		TimeLayout: DefaultTimeLayout,
+		writer:     writer,
+		codepage:   codepage,
+	}
+}
+
+// Write writes the snapshot as a row, preceded by the header when it is the first row.
+// Values are written with the amount of decimals HWiNFO uses for their unit, e.g. three for volts
+// and none for RPM, values that are NaN are left empty.
+func (writer *Writer) Write(snapshot *hwinfoshmem.Snapshot) error {
+	if writer.closed {
+		return errors.New("error writing log: writer is closed")
+	}
+
+	if writer.columns == nil {
+		if err := writer.writeHeader(snapshot); err != nil {
+			return err
+		}
+	}
+
+	location := writer.Location
+	if location == nil {
+		location = time.Local
+	}
+
+	lastUpdate := snapshot.LastUpdate.In(location)
+	row := make([]string, 0, len(writer.columns)+3)
+	row = append(row, lastUpdate.Format(writer.DateLayout), lastUpdate.Format(writer.TimeLayout))
+
+	readings := make(map[hwinfoshmem.ReadingKey]*hwinfoshmem.Reading, len(snapshot.Readings))
+	for _, reading := range snapshot.Readings {
+		readings[reading.Key()] = reading
+	}
+
+	for i, key := range writer.columns {
+		reading, ok := readings[key]
+		if !ok {
+			row = append(row, "")
+			continue
+		}
+
+		row = append(row, formatValue(reading.Value, writer.units[i]))
+	}
+
+	// HWiNFO ends every row with a comma.
+	row = append(row, "")
+
+	var buffer bytes.Buffer
+	csvWriter := csv.NewWriter(&buffer)
+	csvWriter.UseCRLF = true
+
+	if err := csvWriter.WriteAll([][]string{row}); err != nil {
+		return fmt.Errorf("error writing log: %w", err)
+	}
+
+	return writer.writeText(buffer.String())
+}
+
+// Close writes the footer, which repeats the header and contains the name of the sensor of each
+// column. The underlying writer is not closed. Nothing is written when no snapshot was written.
+func (writer *Writer) Close() error {
+	if writer.closed {
+		return nil
+	}
+	writer.closed = true
+
+	if writer.columns == nil {
+		return nil
+	}
+
+	return writer.writeText(quoteRow(writer.header) + quoteRow(writer.footer))
+}
+
+// Size returns the amount of bytes written so far.
+func (writer *Writer) Size() int64 {
+	return writer.size
+}
+
+// writeHeader determines the columns from the snapshot and writes the header.
+func (writer *Writer) writeHeader(snapshot *hwinfoshmem.Snapshot) error {
+	readings := snapshot.Readings
+	if writer.Selector != nil {
+		readings = writer.Selector.Select(snapshot)
+	}
+
+	writer.columns = make([]hwinfoshmem.ReadingKey, 0, len(readings))
+	writer.units = make([]units.Unit, 0, len(readings))
+	writer.header = append(make([]string, 0, len(readings)+3), "Date", "Time")
+	writer.footer = append(make([]string, 0, len(readings)+3), "", "")
+
+	for _, reading := range readings {
+		sensorName := ""
+		if reading.Sensor != nil {
+			sensorName = reading.Sensor.SensorName
+		}
+
+		writer.columns = append(writer.columns, reading.Key())
+		writer.units = append(writer.units, units.Parse(reading.Unit))
+		writer.header = append(writer.header, fmt.Sprintf("%s [%s]", reading.UserLabel, reading.Unit))
+		writer.footer = append(writer.footer, sensorName)
+	}
+
+	if writer.codepage == bytesutil.Codepage65001 {
+		if err := writer.write(utf8ByteOrderMark); err != nil {
+			return err
+		}
+	}
+
+	return writer.writeText(quoteRow(writer.header))
+}
+
+// quoteRow formats the fields as a row of which every field is quoted, like HWiNFO does for the
+// header and footer.
+func quoteRow(fields []string) string {
+	var builder strings.Builder
+	for _, field := range fields {
+		builder.WriteString(`"` + strings.ReplaceAll(field, `"`, `""`) + `",`)
+	}
+	builder.WriteString("\r\n")
+
+	return builder.String()
+}
+
+// writeText encodes the text using the codepage and writes it in a single call.
+func (writer *Writer) writeText(text string) error {
+	encoded, err := bytesutil.StringToAnsi(text, writer.codepage)
+	if err != nil {
+		return err
+	}
+
+	return writer.write(encoded)
+}
+
+func (writer *Writer) write(data []byte) error {
+	written, err := writer.writer.Write(data)
+	writer.size += int64(written)
+	if err != nil {
+		return fmt.Errorf("error writing log: %w", err)
+	}
+
+	return nil
+}
+
+// decimals contains the amount of decimals HWiNFO writes for values of the dimension. Values of
+// other dimensions are written with up to three decimals.
+var decimals = map[units.Dimension]int{
+	units.DimensionTemperature:   1,
+	units.DimensionVoltage:       3,
+	units.DimensionCurrent:       3,
+	units.DimensionPower:         3,
+	units.DimensionFrequency:     1,
+	units.DimensionRotationSpeed: 0,
+	units.DimensionRatio:         1,
+	units.DimensionData:          0,
+}
+
+// formatValue formats the value like HWiNFO, using the words of the unit for booleans.
+func formatValue(value float64, unit units.Unit) string {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return ""
+	}
+
+	if unit.IsBoolean() {
+		word, otherWord, _ := strings.Cut(strings.TrimSpace(unit.Symbol), "/")
+		if (booleanValues[strings.ToLower(word)] != 0) != (value != 0) {
+			return otherWord
+		}
+		return word
+	}
+
+	if precision, ok := decimals[unit.Dimension]; ok {
+		return strconv.FormatFloat(value, 'f', precision, 64)
+	}
+
+	return strconv.FormatFloat(math.Round(value*1000)/1000, 'f', -1, 64)
+}
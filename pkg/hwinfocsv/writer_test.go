@@ -0,0 +1,242 @@
+package hwinfocsv_test
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/hwinfocsv"
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/hwinfoshmem"
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/selector"
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/util/bytesutil"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+var loggedStart = time.Date(2023, 9, 17, 15, 56, 40, 123e6, time.UTC)
+
+// newLoggedSnapshot returns a snapshot of two sensors at the given amount of seconds after
+// loggedStart.
+func newLoggedSnapshot(seconds int) *hwinfoshmem.Snapshot {
+	cpu := &hwinfoshmem.Sensor{SensorId: 0xf0000300, SensorName: "CPU [#0]: AMD Ryzen 9 7950X: Enhanced"}
+	gpu := &hwinfoshmem.Sensor{SensorId: 0xe0002000, SensorName: "GPU [#0]: NVIDIA GeForce RTX 4090"}
+
+	readings := []*hwinfoshmem.Reading{
+		{Sensor: cpu, Type: hwinfoshmem.SENSOR_TYPE_TEMP, Id: 0x1000000, UserLabel: "CPU (Tctl/Tdie)", Unit: "°C", Value: 45.5 + float64(seconds)},
+		{Sensor: cpu, Type: hwinfoshmem.SENSOR_TYPE_OTHER, Id: 0x7000000, UserLabel: "Thermal Throttling (HTC)", Unit: "Yes/No", Value: float64(seconds % 2)},
+		{Sensor: gpu, Type: hwinfoshmem.SENSOR_TYPE_FAN, Id: 0x3000000, UserLabel: "GPU Fan", Unit: "RPM", Value: 1200},
+		{Sensor: gpu, Type: hwinfoshmem.SENSOR_TYPE_POWER, Id: 0x8000000, UserLabel: "GPU Power", Unit: "W", Value: math.NaN()},
+	}
+	cpu.Readings = readings[:2]
+	gpu.Readings = readings[2:]
+
+	return &hwinfoshmem.Snapshot{
+		Active:     true,
+		LastUpdate: loggedStart.Add(time.Duration(seconds) * time.Second),
+		Sensors:    []*hwinfoshmem.Sensor{cpu, gpu},
+		Readings:   readings,
+	}
+}
+
+func ExampleWriter() {
+	var buffer bytes.Buffer
+	writer := hwinfocsv.NewWriter(&buffer, bytesutil.Codepage1252)
+	writer.Location = time.UTC
+	writer.Selector = selector.MustCompile(`sensor~"CPU*"`)
+
+	for i := 0; i < 2; i++ {
+		if err := writer.Write(newLoggedSnapshot(i)); err != nil {
+			fmt.Printf("Failed to write: %s\n", err)
+			return
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		fmt.Printf("Failed to close: %s\n", err)
+		return
+	}
+
+	decoded, _ := bytesutil.AnsiBytesToString(buffer.Bytes(), bytesutil.Codepage1252)
+	fmt.Print(strings.ReplaceAll(decoded, "\r\n", "\n"))
+
+	// Output:
+	// "Date","Time","CPU (Tctl/Tdie) [°C]","Thermal Throttling (HTC) [Yes/No]",
+	// 17.9.2023,15:56:40.123,45.5,No,
+	// 17.9.2023,15:56:41.123,46.5,Yes,
+	// "Date","Time","CPU (Tctl/Tdie) [°C]","Thermal Throttling (HTC) [Yes/No]",
+	// "","","CPU [#0]: AMD Ryzen 9 7950X: Enhanced","CPU [#0]: AMD Ryzen 9 7950X: Enhanced",
+}
+
+func TestWriterHwinfoLog(t *testing.T) {
+	reader, err := hwinfocsv.NewReader(bytes.NewReader(sensorsLog), bytesutil.Codepage1252)
+	if err != nil {
+		t.Fatalf("failed to open log: %v", err)
+	}
+	reader.Location = time.UTC
+
+	var buffer bytes.Buffer
+	writer := hwinfocsv.NewWriter(&buffer, bytesutil.Codepage1252)
+	writer.Location = time.UTC
+
+	for _, snapshot := range readAll(t, reader) {
+		if err = writer.Write(snapshot); err != nil {
+			t.Fatalf("failed to write: %v", err)
+		}
+	}
+
+	if err = writer.Close(); err != nil {
+		t.Fatalf("failed to close: %v", err)
+	}
+
+	// The log written by HWiNFO is reproduced exactly.
+	if !bytes.Equal(buffer.Bytes(), sensorsLog) {
+		t.Errorf("unexpected log:\n%s\nexpected:\n%s", buffer.Bytes(), sensorsLog)
+	}
+}
+
+func TestWriterRoundTrip(t *testing.T) {
+	for _, codepage := range []bytesutil.Codepage{bytesutil.Codepage1252, bytesutil.Codepage65001} {
+		t.Run(codepage.String(), func(t *testing.T) {
+			var buffer bytes.Buffer
+			writer := hwinfocsv.NewWriter(&buffer, codepage)
+
+			for i := 0; i < 3; i++ {
+				snapshot := newLoggedSnapshot(i)
+				if i == 1 {
+					// Readings that disappear are left empty.
+					snapshot.Readings = snapshot.Readings[1:]
+				}
+
+				if err := writer.Write(snapshot); err != nil {
+					t.Fatalf("failed to write: %v", err)
+				}
+			}
+
+			if err := writer.Close(); err != nil {
+				t.Fatalf("failed to close: %v", err)
+			}
+
+			if writer.Size() != int64(buffer.Len()) {
+				t.Errorf("expected size %d, got %d", buffer.Len(), writer.Size())
+			}
+
+			reader, err := hwinfocsv.NewReader(&buffer, bytesutil.Codepage1252)
+			if err != nil {
+				t.Fatalf("failed to open log: %v", err)
+			}
+
+			snapshots := readAll(t, reader)
+			if len(snapshots) != 3 {
+				t.Fatalf("expected 3 snapshots, got %d", len(snapshots))
+			}
+
+			for i, snapshot := range snapshots {
+				expected := newLoggedSnapshot(i)
+				if !snapshot.LastUpdate.Equal(expected.LastUpdate) {
+					t.Errorf("expected last update %s, got %s", expected.LastUpdate, snapshot.LastUpdate)
+				}
+
+				if len(snapshot.Readings) != len(expected.Readings) {
+					t.Fatalf("expected %d readings, got %d", len(expected.Readings), len(snapshot.Readings))
+				}
+
+				for j, reading := range snapshot.Readings {
+					expectedReading := expected.Readings[j]
+					if i == 1 && j == 0 {
+						expectedReading.Value = math.NaN()
+					}
+
+					if reading.UserLabel != expectedReading.UserLabel || reading.Unit != expectedReading.Unit ||
+						reading.Type != expectedReading.Type || reading.Sensor.SensorName != expectedReading.Sensor.SensorName {
+						t.Errorf("reading %d differs, expected %+v, got %+v", j, expectedReading, reading)
+					}
+
+					if reading.Value != expectedReading.Value && !(math.IsNaN(reading.Value) && math.IsNaN(expectedReading.Value)) {
+						t.Errorf("expected value %v of reading %d, got %v", expectedReading.Value, j, reading.Value)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestLogger(t *testing.T) {
+	directory := t.TempDir()
+	logger := hwinfocsv.NewLogger(filepath.Join(directory, "sensors.csv"), bytesutil.Codepage1252)
+	logger.Location = time.UTC
+	logger.MaxDuration = 10 * time.Second
+
+	var logPaths []string
+	for i := 0; i < 25; i++ {
+		if err := logger.Write(newLoggedSnapshot(i)); err != nil {
+			t.Fatalf("failed to write: %v", err)
+		}
+
+		if path := logger.CurrentPath(); !slices.Contains(logPaths, path) {
+			logPaths = append(logPaths, path)
+		}
+	}
+
+	if err := logger.Close(); err != nil {
+		t.Fatalf("failed to close: %v", err)
+	}
+
+	if len(logPaths) != 3 {
+		t.Errorf("expected 3 logs, got %v", logPaths)
+	}
+
+	// A log that reaches the maximum size is closed, and a counter is added to the name of a log
+	// when a log with the same name exists.
+	logger.MaxSize = 1
+	for i := 0; i < 2; i++ {
+		if err := logger.Write(newLoggedSnapshot(24)); err != nil {
+			t.Fatalf("failed to write: %v", err)
+		}
+
+		if logger.CurrentPath() != "" {
+			t.Errorf("expected the log to be closed after reaching the maximum size, got %s", logger.CurrentPath())
+		}
+	}
+
+	paths, err := filepath.Glob(filepath.Join(directory, "*"))
+	if err != nil {
+		t.Fatalf("failed to list logs: %v", err)
+	}
+
+	expectedNames := []string{
+		"sensors-20230917-155640.csv",
+		"sensors-20230917-155650.csv",
+		"sensors-20230917-155700.csv",
+		"sensors-20230917-155704.csv",
+		"sensors-20230917-155704-1.csv",
+	}
+	names := make([]string, 0, len(paths))
+	for _, path := range paths {
+		names = append(names, filepath.Base(path))
+	}
+	sortedNames := slices.Clone(expectedNames)
+	slices.Sort(sortedNames)
+	if !slices.Equal(names, sortedNames) {
+		t.Fatalf("expected logs %v, got %v", expectedNames, names)
+	}
+
+	for i, expectedRows := range []int{10, 10, 5, 1, 1} {
+		file, err := os.Open(filepath.Join(directory, expectedNames[i]))
+		if err != nil {
+			t.Fatalf("failed to open log: %v", err)
+		}
+
+		reader, err := hwinfocsv.NewReader(file, bytesutil.Codepage1252)
+		if err != nil {
+			t.Fatalf("failed to read log %s: %v", expectedNames[i], err)
+		}
+
+		if rows := len(readAll(t, reader)); rows != expectedRows {
+			t.Errorf("expected %d rows in %s, got %d", expectedRows, expectedNames[i], rows)
+		}
+		_ = file.Close()
+	}
+}
@@ -1,25 +1,64 @@
 package hwinfoshmem
 
-import "unsafe"
+import (
+	"fmt"
+	"unsafe"
+)
 
 // BytesReader allows for extracting the header, sensors, and readings from a copy of HWiNFO's
 // shared memory.
 // A copy can be made using [MemoryReader.Copy].
 //
+// The bytes are validated before use, see [Reader], which makes BytesReader safe to use with
+// data from untrusted sources such as files or the network.
+//
 // BytesReader has an initializer function, [NewBytesReader].
 type BytesReader struct {
 	Bytes []byte
+
+	// The snapshot file the bytes were taken from, nil when the bytes were not wrapped in a
+	// snapshot file.
+	SnapshotFile *SnapshotFile
+
 	*Reader
+
+	// err is returned when reading, it is set when the bytes are an invalid snapshot file.
+	err error
 }
 
+// NewBytesReader creates a reader for a copy of the shared memory.
+// The bytes can also be a [SnapshotFile], in which case Bytes is set to its data after verifying
+// the checksum. When that fails, the error is returned when reading.
 func NewBytesReader(bytes []byte) *BytesReader {
 	bytesReader := &BytesReader{
 		Bytes: bytes,
 	}
+
+	if IsSnapshotFile(bytes) {
+		file := &SnapshotFile{}
+		if err := file.UnmarshalBinary(bytes); err != nil {
+			bytesReader.err = err
+		} else {
+			bytesReader.Bytes = file.Data
+			bytesReader.SnapshotFile = file
+		}
+	}
+
 	bytesReader.Reader = &Reader{
 		GetPointer: func() (uintptr, error) {
+			if bytesReader.err != nil {
+				return 0, bytesReader.err
+			}
+
+			if len(bytesReader.Bytes) == 0 {
+				return 0, fmt.Errorf("%w: no bytes to read", ErrTruncated)
+			}
+
 			return uintptr(unsafe.Pointer(&bytesReader.Bytes[0])), nil
 		},
+		GetSize: func() (uintptr, error) {
+			return uintptr(len(bytesReader.Bytes)), nil
+		},
 	}
 
 	return bytesReader
@@ -0,0 +1,12 @@
+//go:build !windows
+
+package hwinfoshmem
+
+import "github.com/MatthiasKunnen/hwinfo-go/pkg/util/bytesutil"
+
+// SystemCodepage returns the codepage used for the ASCII strings when no other codepage is
+// configured. HWiNFO only runs on Windows, so on other systems this is the most common Windows
+// codepage, Windows-1252.
+func SystemCodepage() bytesutil.Codepage {
+	return bytesutil.Codepage1252
+}
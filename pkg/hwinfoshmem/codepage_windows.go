@@ -0,0 +1,12 @@
+package hwinfoshmem
+
+import (
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/util/bytesutil"
+	"golang.org/x/sys/windows"
+)
+
+// SystemCodepage returns the ANSI codepage of the system which is the codepage HWiNFO uses for
+// the ASCII strings.
+func SystemCodepage() bytesutil.Codepage {
+	return bytesutil.Codepage(windows.GetACP())
+}
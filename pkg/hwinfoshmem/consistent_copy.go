@@ -0,0 +1,80 @@
+package hwinfoshmem
+
+import (
+	"errors"
+	"fmt"
+	"time"
+	"unsafe"
+)
+
+// ErrTornRead is returned by [Reader.CopyConsistent] when the data kept changing while it was
+// being copied.
+var ErrTornRead = errors.New("data changed while copying")
+
+// consistentCopyRetryDelay is the time to wait before trying to copy again, giving the producer
+// the opportunity to finish its update.
+const consistentCopyRetryDelay = time.Millisecond
+
+// CopyConsistent copies the data without holding the lock, see [MemoryReader.Copy].
+// This prevents pausing HWiNFO but allows it to change the data while it is being copied.
+// To detect this, the header is read before and after copying. When the header changed, e.g. a
+// new LastUpdate, sensor or reading amount, or section offset, the copy is discarded and another
+// attempt is made.
+// After the given amount of attempts, an error wrapping [ErrTornRead] is returned. Attempts must
+// be at least 1.
+//
+// Changes to values that are not accompanied by a change of the header cannot be detected.
+// Lock enforcement must be disabled for this function to work without the lock, e.g. using
+// [MemoryReader.DisableLockEnforcement].
+func (reader *Reader) CopyConsistent(attempts int) (*BytesReader, error) {
+	if attempts < 1 {
+		return nil, fmt.Errorf("invalid amount of attempts %d, must be at least 1", attempts)
+	}
+
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if attempt > 1 {
+			time.Sleep(consistentCopyRetryDelay)
+		}
+
+		copied, ok, err := reader.copyOnce()
+		if err != nil {
+			return nil, err
+		}
+
+		if ok {
+			return copied, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: gave up after %d attempts", ErrTornRead, attempts)
+}
+
+// copyOnce copies the data described by the header.
+// Reports false when the header changed during the copy.
+func (reader *Reader) copyOnce() (*BytesReader, bool, error) {
+	header, err := reader.GetHeader()
+	if err != nil {
+		return nil, false, err
+	}
+	before := *header
+
+	pointer, err := reader.getValidatedPointer(&before)
+	if err != nil {
+		return nil, false, err
+	}
+
+	size := max(headerSize, sensorSectionEnd(&before), readingSectionEnd(&before))
+	byteSlice := make([]byte, size)
+	copy(byteSlice, unsafe.Slice((*byte)(unsafe.Pointer(pointer)), size))
+
+	after := *(*HwinfoHeader)(unsafe.Pointer(pointer))
+	copied := *(*HwinfoHeader)(unsafe.Pointer(&byteSlice[0]))
+	if before != after || before != copied {
+		return nil, false, nil
+	}
+
+	bytesReader := NewBytesReader(byteSlice)
+	bytesReader.Codepage = reader.Codepage
+
+	return bytesReader, true, nil
+}
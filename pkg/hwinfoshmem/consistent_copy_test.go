@@ -0,0 +1,75 @@
+package hwinfoshmem_test
+
+import (
+	"encoding/binary"
+	"errors"
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/hwinfoshmem"
+	"math"
+	"testing"
+	"unsafe"
+)
+
+// newTearingReader returns a Reader over a copy of data that behaves like HWiNFO updating the
+// data while it is being copied: every time the data is accessed, LastUpdate is incremented until
+// it reaches the given value.
+func newTearingReader(until uint64) *hwinfoshmem.Reader {
+	dataCopy := make([]byte, len(data))
+	copy(dataCopy, data)
+
+	tear := func() {
+		if lastUpdate := binary.LittleEndian.Uint64(dataCopy[offsetLastUpdate:]); lastUpdate < until {
+			binary.LittleEndian.PutUint64(dataCopy[offsetLastUpdate:], lastUpdate+1)
+		}
+	}
+
+	return &hwinfoshmem.Reader{
+		GetPointer: func() (uintptr, error) {
+			tear()
+			return uintptr(unsafe.Pointer(&dataCopy[0])), nil
+		},
+		GetSize: func() (uintptr, error) {
+			tear()
+			return uintptr(len(dataCopy)), nil
+		},
+	}
+}
+
+func TestCopyConsistent(t *testing.T) {
+	bytesReader, err := newTearingReader(1694966202).CopyConsistent(10)
+	if err != nil {
+		t.Fatalf("failed to copy: %v", err)
+	}
+
+	info, err := bytesReader.GetHeader()
+	if err != nil {
+		t.Fatalf("failed to get header: %v", err)
+	}
+
+	// Copies are discarded as long as the data changes, the copy contains the final update.
+	if lastUpdate := info.GetLastUpdate(); lastUpdate != 1694966202 {
+		t.Errorf("expected the last update after the data stopped changing, got %d", lastUpdate)
+	}
+
+	readings, err := bytesReader.GetReadings(info)
+	if err != nil {
+		t.Fatalf("failed to get readings: %v", err)
+	}
+
+	if len(readings) != 7 {
+		t.Errorf("expected 7 readings, got %d", len(readings))
+	}
+}
+
+func TestCopyConsistentGivesUp(t *testing.T) {
+	_, err := newTearingReader(math.MaxUint64).CopyConsistent(3)
+	if !errors.Is(err, hwinfoshmem.ErrTornRead) {
+		t.Errorf("expected ErrTornRead, got %v", err)
+	}
+}
+
+func TestCopyConsistentInvalidAttempts(t *testing.T) {
+	_, err := newTearingReader(0).CopyConsistent(0)
+	if err == nil || errors.Is(err, hwinfoshmem.ErrTornRead) {
+		t.Errorf("expected an error for 0 attempts, got %v", err)
+	}
+}
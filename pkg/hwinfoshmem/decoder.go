@@ -0,0 +1,201 @@
+package hwinfoshmem
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/util/bytesutil"
+)
+
+// Decoder extracts the header, sensors, and readings from a copy of HWiNFO's shared memory without
+// using unsafe.
+// Unlike [Reader], the results are copies that are owned by the caller and stay valid regardless of
+// what happens to the bytes afterward.
+// This makes it slower than [Reader] but usable on any architecture, independent of memory
+// alignment.
+//
+// The layout is detected using [DetectLayout] which allows Decoder to read data written by older
+// HWiNFO versions. Fields that are absent in the layout are zero, except for the UTF-8 strings
+// which are filled using their ASCII counterpart.
+// Sizes larger than [HwinfoSensor] and [HwinfoReading] are supported, the extra trailing bytes
+// are skipped.
+//
+// Decoder has an initializer function, [NewDecoder].
+type Decoder struct {
+	Bytes []byte
+
+	// Codepage used to convert the ASCII strings, see [HwinfoSensorStringAscii].
+	// When zero, [SystemCodepage] is used.
+	Codepage bytesutil.Codepage
+}
+
+func NewDecoder(bytes []byte) *Decoder {
+	return &Decoder{
+		Bytes: bytes,
+	}
+}
+
+// GetHeader returns a copy of the header.
+// When the header has revision 0, PollingPeriodInMs is zero.
+func (decoder *Decoder) GetHeader() (*HwinfoHeader, error) {
+	if len(decoder.Bytes) < headerSizeRevision0 {
+		return nil, fmt.Errorf(
+			"%w: header needs %d bytes, got %d",
+			ErrTruncated,
+			headerSizeRevision0,
+			len(decoder.Bytes),
+		)
+	}
+
+	info := &HwinfoHeader{}
+	if err := decodeElement(decoder.Bytes, headerSize, info); err != nil {
+		return nil, fmt.Errorf("error decoding header: %w", err)
+	}
+
+	if info.Revision == 0 {
+		info.PollingPeriodInMs = 0
+	} else if uint64(len(decoder.Bytes)) < headerSize {
+		return nil, fmt.Errorf(
+			"%w: header needs %d bytes, got %d",
+			ErrTruncated,
+			headerSize,
+			len(decoder.Bytes),
+		)
+	}
+
+	return info, nil
+}
+
+// GetCodepage returns the codepage used to convert the ASCII strings.
+func (decoder *Decoder) GetCodepage() bytesutil.Codepage {
+	if decoder.Codepage == 0 {
+		return SystemCodepage()
+	}
+
+	return decoder.Codepage
+}
+
+// GetLayout returns the layout of the data described by the header, see [DetectLayout].
+func (decoder *Decoder) GetLayout(info *HwinfoHeader) (LayoutInfo, error) {
+	layout, err := DetectLayout(info)
+	if err != nil {
+		return layout, err
+	}
+
+	return layout, validateHeader(info, uint64(len(decoder.Bytes)), layout.minimumSizes())
+}
+
+// GetSensors returns copies of the sensors described by the header.
+func (decoder *Decoder) GetSensors(info *HwinfoHeader) ([]*HwinfoSensor, error) {
+	layout, err := decoder.GetLayout(info)
+	if err != nil {
+		return nil, err
+	}
+
+	sensors := make([]*HwinfoSensor, info.SensorAmount)
+
+	for i := uint32(0); i < info.SensorAmount; i++ {
+		offset := uint64(info.SensorSectionOffset) + uint64(i)*uint64(info.SensorSize)
+		sensor := &HwinfoSensor{}
+		if err := decodeElement(decoder.Bytes[offset:], layout.minimumSizes().sensor, sensor); err != nil {
+			return nil, fmt.Errorf("error decoding sensor %d: %w", i, err)
+		}
+
+		if !layout.HasUtf8Strings {
+			name, err := sensor.SensorNameAscii.Decode(decoder.GetCodepage())
+			if err != nil {
+				return nil, fmt.Errorf("error decoding name of sensor %d: %w", i, err)
+			}
+			bytesutil.StringToUtf8Bytes(sensor.SensorName[:], name)
+		}
+
+		sensors[i] = sensor
+	}
+
+	return sensors, nil
+}
+
+// GetReadings returns copies of all readings described by the header.
+func (decoder *Decoder) GetReadings(info *HwinfoHeader) ([]*HwinfoReading, error) {
+	layout, err := decoder.GetLayout(info)
+	if err != nil {
+		return nil, err
+	}
+
+	readings := make([]*HwinfoReading, info.ReadingAmount)
+
+	for i := uint32(0); i < info.ReadingAmount; i++ {
+		reading, err := decoder.decodeReading(info, layout, i)
+		if err != nil {
+			return nil, err
+		}
+		readings[i] = reading
+	}
+
+	return readings, nil
+}
+
+// GetReadingsById returns copies of the readings that match the given sensor index/id
+// combinations.
+func (decoder *Decoder) GetReadingsById(info *HwinfoHeader, readingIds []ReadingIdSensorCombo) ([]*HwinfoReading, error) {
+	layout, err := decoder.GetLayout(info)
+	if err != nil {
+		return nil, err
+	}
+
+	readings := make([]*HwinfoReading, 0)
+
+	for i := uint32(0); i < info.ReadingAmount; i++ {
+		offset := uint64(info.ReadingSectionOffset) + uint64(i)*uint64(info.ReadingSize)
+		sensorIndex := binary.LittleEndian.Uint32(decoder.Bytes[offset+4:])
+		readingId := binary.LittleEndian.Uint32(decoder.Bytes[offset+8:])
+
+		for _, indexInfo := range readingIds {
+			if indexInfo.SensorIndex == sensorIndex && indexInfo.Id == readingId {
+				reading, err := decoder.decodeReading(info, layout, i)
+				if err != nil {
+					return nil, err
+				}
+				readings = append(readings, reading)
+			}
+		}
+	}
+
+	return readings, nil
+}
+
+func (decoder *Decoder) decodeReading(info *HwinfoHeader, layout LayoutInfo, index uint32) (*HwinfoReading, error) {
+	offset := uint64(info.ReadingSectionOffset) + uint64(index)*uint64(info.ReadingSize)
+	reading := &HwinfoReading{}
+	if err := decodeElement(decoder.Bytes[offset:], layout.minimumSizes().reading, reading); err != nil {
+		return nil, fmt.Errorf("error decoding reading %d: %w", index, err)
+	}
+
+	if !layout.HasUtf8Strings {
+		label, err := reading.UserLabelAscii.Decode(decoder.GetCodepage())
+		if err != nil {
+			return nil, fmt.Errorf("error decoding label of reading %d: %w", index, err)
+		}
+		bytesutil.StringToUtf8Bytes(reading.UserLabel[:], label)
+
+		unit, err := reading.UnitAscii.Decode(decoder.GetCodepage())
+		if err != nil {
+			return nil, fmt.Errorf("error decoding unit of reading %d: %w", index, err)
+		}
+		bytesutil.StringToUtf8Bytes(reading.Unit[:], unit)
+	}
+
+	return reading, nil
+}
+
+// decodeElement decodes the first size bytes of the little endian data into the struct pointed to
+// by element. When the data or size is shorter than the struct, the remaining fields are zero.
+func decodeElement(data []byte, size uint64, element any) error {
+	elementBytes := make([]byte, binary.Size(element))
+	if uint64(len(data)) < size {
+		size = uint64(len(data))
+	}
+	copy(elementBytes, data[:size])
+
+	return binary.Read(bytes.NewReader(elementBytes), binary.LittleEndian, element)
+}
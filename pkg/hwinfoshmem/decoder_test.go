@@ -0,0 +1,130 @@
+package hwinfoshmem_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/hwinfoshmem"
+	"reflect"
+	"testing"
+)
+
+// readAll returns copies of the header, sensors, and readings returned by the source.
+func readAll(t *testing.T, source hwinfoshmem.Source) (hwinfoshmem.HwinfoHeader, []hwinfoshmem.HwinfoSensor, []hwinfoshmem.HwinfoReading) {
+	t.Helper()
+
+	info, err := source.GetHeader()
+	if err != nil {
+		t.Fatalf("failed to get header: %v", err)
+	}
+
+	sensorPointers, err := source.GetSensors(info)
+	if err != nil {
+		t.Fatalf("failed to get sensors: %v", err)
+	}
+
+	readingPointers, err := source.GetReadings(info)
+	if err != nil {
+		t.Fatalf("failed to get readings: %v", err)
+	}
+
+	sensors := make([]hwinfoshmem.HwinfoSensor, len(sensorPointers))
+	for i, sensor := range sensorPointers {
+		sensors[i] = *sensor
+	}
+
+	readings := make([]hwinfoshmem.HwinfoReading, len(readingPointers))
+	for i, reading := range readingPointers {
+		readings[i] = *reading
+	}
+
+	return *info, sensors, readings
+}
+
+func TestDecoderEquivalence(t *testing.T) {
+	info, sensors, readings := readAll(t, hwinfoshmem.NewBytesReader(data))
+	decodedInfo, decodedSensors, decodedReadings := readAll(t, hwinfoshmem.NewDecoder(data))
+
+	if !reflect.DeepEqual(info, decodedInfo) {
+		t.Errorf("headers differ:\n%+v\n%+v", info, decodedInfo)
+	}
+
+	if !reflect.DeepEqual(sensors, decodedSensors) {
+		t.Errorf("sensors differ")
+	}
+
+	if !reflect.DeepEqual(readings, decodedReadings) {
+		t.Errorf("readings differ")
+	}
+}
+
+func TestDecoderOwnsResults(t *testing.T) {
+	dataCopy := make([]byte, len(data))
+	copy(dataCopy, data)
+	decoder := hwinfoshmem.NewDecoder(dataCopy)
+
+	info, err := decoder.GetHeader()
+	if err != nil {
+		t.Fatalf("failed to get header: %v", err)
+	}
+
+	readings, err := decoder.GetReadings(info)
+	if err != nil {
+		t.Fatalf("failed to get readings: %v", err)
+	}
+
+	clear(dataCopy)
+
+	if label := readings[0].UserLabel.String(); label != "CPU (Tctl/Tdie)" {
+		t.Errorf("expected reading to be unaffected by changes to the bytes, got label %q", label)
+	}
+}
+
+// withPadding returns a copy of data where each sensor and reading is followed by extra bytes,
+// simulating a newer HWiNFO version that added fields.
+func withPadding(sensorPadding uint32, readingPadding uint32) []byte {
+	var info hwinfoshmem.HwinfoHeader
+	_ = binary.Read(bytes.NewReader(data), binary.LittleEndian, &info)
+
+	result := make([]byte, 0)
+	result = append(result, data[:info.SensorSectionOffset]...)
+
+	for i := uint32(0); i < info.SensorAmount; i++ {
+		offset := info.SensorSectionOffset + i*info.SensorSize
+		result = append(result, data[offset:offset+info.SensorSize]...)
+		result = append(result, make([]byte, sensorPadding)...)
+	}
+
+	readingSectionOffset := uint32(len(result))
+	for i := uint32(0); i < info.ReadingAmount; i++ {
+		offset := info.ReadingSectionOffset + i*info.ReadingSize
+		result = append(result, data[offset:offset+info.ReadingSize]...)
+		result = append(result, 0xFF)
+		result = append(result, make([]byte, readingPadding-1)...)
+	}
+
+	binary.LittleEndian.PutUint32(result[offsetSensorSize:], info.SensorSize+sensorPadding)
+	binary.LittleEndian.PutUint32(result[offsetReadingSectionOffset:], readingSectionOffset)
+	binary.LittleEndian.PutUint32(result[offsetReadingSize:], info.ReadingSize+readingPadding)
+
+	return result
+}
+
+func TestDecoderLargerElements(t *testing.T) {
+	_, sensors, readings := readAll(t, hwinfoshmem.NewBytesReader(data))
+	padded := withPadding(40, 16)
+
+	for name, source := range map[string]hwinfoshmem.Source{
+		"Decoder":     hwinfoshmem.NewDecoder(padded),
+		"BytesReader": hwinfoshmem.NewBytesReader(padded),
+	} {
+		_, paddedSensors, paddedReadings := readAll(t, source)
+
+		if !reflect.DeepEqual(sensors, paddedSensors) {
+			t.Errorf("%s: sensors differ", name)
+		}
+
+		if !reflect.DeepEqual(readings, paddedReadings) {
+			t.Errorf("%s: readings differ", name)
+		}
+	}
+}
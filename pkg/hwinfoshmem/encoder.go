@@ -0,0 +1,188 @@
+package hwinfoshmem
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/util/bytesutil"
+)
+
+// EncodeImage creates a byte array in the layout of HWiNFO's shared memory containing the header,
+// sensors, and readings.
+// The result can be read using [NewBytesReader] or [NewDecoder].
+//
+// The layout is determined by the Version and Revision of the header, see [LayoutInfo].
+// Fields that do not exist in the layout are not written.
+// The amounts in the header are set to the amount of sensors and readings.
+// When zero, the offsets and sizes of the sections are set to place the sensor section directly
+// after the header and the reading section directly after the sensor section.
+// Otherwise, they are used as-is which allows creating byte exact copies of existing data.
+// Sizes larger than needed for the layout result in sensors and readings padded with nul bytes.
+func EncodeImage(info HwinfoHeader, sensors []HwinfoSensor, readings []HwinfoReading) ([]byte, error) {
+	minimum := layoutSizes(info.Version, info.Revision)
+
+	info.SensorAmount = uint32(len(sensors))
+	info.ReadingAmount = uint32(len(readings))
+
+	if info.SensorSize == 0 {
+		info.SensorSize = uint32(minimum.sensor)
+	}
+
+	if info.ReadingSize == 0 {
+		info.ReadingSize = uint32(minimum.reading)
+	}
+
+	if info.SensorSectionOffset == 0 {
+		info.SensorSectionOffset = uint32(minimum.header)
+	}
+
+	if info.ReadingSectionOffset == 0 {
+		info.ReadingSectionOffset = uint32(sensorSectionEnd(&info))
+	}
+
+	if err := validateLayout(&info, minimum); err != nil {
+		return nil, fmt.Errorf("error encoding image: %w", err)
+	}
+
+	size := max(minimum.header, sensorSectionEnd(&info), readingSectionEnd(&info))
+	image := make([]byte, size)
+
+	if err := encodeElement(image, minimum.header, info); err != nil {
+		return nil, fmt.Errorf("error encoding header: %w", err)
+	}
+
+	for i, sensor := range sensors {
+		offset := uint64(info.SensorSectionOffset) + uint64(i)*uint64(info.SensorSize)
+		if err := encodeElement(image[offset:], minimum.sensor, sensor); err != nil {
+			return nil, fmt.Errorf("error encoding sensor %d: %w", i, err)
+		}
+	}
+
+	for i, reading := range readings {
+		offset := uint64(info.ReadingSectionOffset) + uint64(i)*uint64(info.ReadingSize)
+		if err := encodeElement(image[offset:], minimum.reading, reading); err != nil {
+			return nil, fmt.Errorf("error encoding reading %d: %w", i, err)
+		}
+	}
+
+	return image, nil
+}
+
+// EncodeSnapshot creates a byte array in the layout of HWiNFO's shared memory containing the data
+// of the snapshot, see [EncodeImage].
+// The ASCII strings are encoded using the given codepage, characters that do not exist in the
+// codepage are replaced by a question mark.
+//
+// The status is taken from [Snapshot.Status], or from [Snapshot.Active] when Status is empty.
+// The sizes of the sensors and readings are taken from [Snapshot.Layout] when set.
+// Readings refer to the position of their [Reading.Sensor] in [Snapshot.Sensors], or to
+// [Reading.SensorIndex] when the sensor is not part of the snapshot.
+func EncodeSnapshot(snapshot *Snapshot, codepage bytesutil.Codepage) ([]byte, error) {
+	info := HwinfoHeader{
+		Version:           snapshot.Version,
+		Revision:          snapshot.Revision,
+		SensorSize:        snapshot.Layout.SensorSize,
+		ReadingSize:       snapshot.Layout.ReadingSize,
+		PollingPeriodInMs: uint32(snapshot.PollingPeriod.Milliseconds()),
+	}
+	if snapshot.Status == "" {
+		info.SetActive(snapshot.Active)
+	} else {
+		copy(info.Status[:], snapshot.Status)
+	}
+	info.SetLastUpdateTime(snapshot.LastUpdate)
+
+	sensors := make([]HwinfoSensor, len(snapshot.Sensors))
+	sensorIndices := make(map[*Sensor]uint32, len(snapshot.Sensors))
+
+	for i, sensor := range snapshot.Sensors {
+		nameOriginal, err := NewHwinfoSensorStringAscii(sensor.SensorNameOriginal, codepage)
+		if err != nil {
+			return nil, err
+		}
+
+		name, err := NewHwinfoSensorStringAscii(sensor.SensorName, codepage)
+		if err != nil {
+			return nil, err
+		}
+
+		sensors[i] = HwinfoSensor{
+			SensorId:                sensor.SensorId,
+			SensorInstance:          sensor.SensorInstance,
+			SensorNameOriginalAscii: nameOriginal,
+			SensorNameAscii:         name,
+			SensorName:              NewHwinfoSensorStringUtf8(sensor.SensorName),
+		}
+		sensorIndices[sensor] = uint32(i)
+	}
+
+	readings := make([]HwinfoReading, len(snapshot.Readings))
+
+	for i, reading := range snapshot.Readings {
+		originalLabel, err := NewHwinfoSensorStringAscii(reading.OriginalLabel, codepage)
+		if err != nil {
+			return nil, err
+		}
+
+		userLabel, err := NewHwinfoSensorStringAscii(reading.UserLabel, codepage)
+		if err != nil {
+			return nil, err
+		}
+
+		unit, err := NewHwinfoUnitStringAscii(reading.Unit, codepage)
+		if err != nil {
+			return nil, err
+		}
+
+		sensorIndex, ok := sensorIndices[reading.Sensor]
+		if !ok {
+			sensorIndex = reading.SensorIndex
+		}
+
+		readings[i] = HwinfoReading{
+			Type:               reading.Type,
+			SensorIndex:        sensorIndex,
+			Id:                 reading.Id,
+			OriginalLabelAscii: originalLabel,
+			UserLabelAscii:     userLabel,
+			UnitAscii:          unit,
+			Value:              NewHwinfoFloat64(reading.Value),
+			ValueMin:           NewHwinfoFloat64(reading.ValueMin),
+			ValueMax:           NewHwinfoFloat64(reading.ValueMax),
+			ValueAvg:           NewHwinfoFloat64(reading.ValueAvg),
+			UserLabel:          NewHwinfoSensorStringUtf8(reading.UserLabel),
+			Unit:               NewHwinfoUnitStringUtf8(reading.Unit),
+		}
+	}
+
+	return EncodeImage(info, sensors, readings)
+}
+
+// layoutSizes returns the sizes of the header and elements in the layout of the given version and
+// revision.
+func layoutSizes(version uint32, revision uint32) elementSizes {
+	layout := LayoutInfo{
+		HeaderSize:       headerSizeRevision0,
+		HasPollingPeriod: revision >= 1,
+		HasUtf8Strings:   version >= 2,
+	}
+
+	if layout.HasPollingPeriod {
+		layout.HeaderSize = uint32(headerSize)
+	}
+
+	return layout.minimumSizes()
+}
+
+// encodeElement writes the first size bytes of the little endian representation of element to
+// data.
+func encodeElement(data []byte, size uint64, element any) error {
+	buffer := bytes.NewBuffer(make([]byte, 0, binary.Size(element)))
+	if err := binary.Write(buffer, binary.LittleEndian, element); err != nil {
+		return err
+	}
+
+	copy(data[:size], buffer.Bytes())
+
+	return nil
+}
@@ -0,0 +1,123 @@
+package hwinfoshmem_test
+
+import (
+	"bytes"
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/hwinfoshmem"
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/util/bytesutil"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestEncodeImageRoundTrip(t *testing.T) {
+	info, sensors, readings := readAll(t, hwinfoshmem.NewDecoder(data))
+
+	image, err := hwinfoshmem.EncodeImage(info, sensors, readings)
+	if err != nil {
+		t.Fatalf("failed to encode: %v", err)
+	}
+
+	if !bytes.Equal(image, data) {
+		t.Errorf("encoded image differs from the original")
+	}
+}
+
+func TestEncodeSnapshotRoundTrip(t *testing.T) {
+	bytesReader := hwinfoshmem.NewBytesReader(data)
+	bytesReader.Codepage = bytesutil.Codepage1252
+
+	info, err := bytesReader.GetHeader()
+	if err != nil {
+		t.Fatalf("failed to get header: %v", err)
+	}
+
+	snapshot, err := hwinfoshmem.NewSnapshot(bytesReader, info)
+	if err != nil {
+		t.Fatalf("failed to create snapshot: %v", err)
+	}
+
+	image, err := hwinfoshmem.EncodeSnapshot(snapshot, bytesutil.Codepage1252)
+	if err != nil {
+		t.Fatalf("failed to encode: %v", err)
+	}
+
+	encodedReader := hwinfoshmem.NewBytesReader(image)
+	encodedReader.Codepage = bytesutil.Codepage1252
+
+	encodedInfo, err := encodedReader.GetHeader()
+	if err != nil {
+		t.Fatalf("failed to get header of encoded image: %v", err)
+	}
+
+	encodedSnapshot, err := hwinfoshmem.NewSnapshot(encodedReader, encodedInfo)
+	if err != nil {
+		t.Fatalf("failed to create snapshot of encoded image: %v", err)
+	}
+
+	if !reflect.DeepEqual(snapshot, encodedSnapshot) {
+		t.Errorf("snapshots differ")
+	}
+}
+
+func TestEncodeHistoricLayouts(t *testing.T) {
+	for _, revision := range []uint32{0, 1} {
+		for _, version := range []uint32{1, 2} {
+			snapshot := &hwinfoshmem.Snapshot{
+				Active:        true,
+				Version:       version,
+				Revision:      revision,
+				LastUpdate:    time.Unix(1694966200, 0),
+				PollingPeriod: 2 * time.Second,
+				Sensors: []*hwinfoshmem.Sensor{
+					{SensorId: 0xF0000300, SensorNameOriginal: "CPU", SensorName: "Processor"},
+				},
+			}
+			snapshot.Readings = []*hwinfoshmem.Reading{{
+				Sensor:        snapshot.Sensors[0],
+				Type:          hwinfoshmem.SENSOR_TYPE_TEMP,
+				Id:            0x1000000,
+				OriginalLabel: "CPU (Tctl/Tdie)",
+				UserLabel:     "CPU",
+				Unit:          "°C",
+				Value:         47.25,
+			}}
+
+			image, err := hwinfoshmem.EncodeSnapshot(snapshot, bytesutil.Codepage1252)
+			if err != nil {
+				t.Fatalf("version %d revision %d: failed to encode: %v", version, revision, err)
+			}
+
+			expectedSize := map[[2]uint32]int{
+				{1, 0}: 44 + 264 + 316,
+				{2, 0}: 44 + 392 + 460,
+				{1, 1}: 48 + 264 + 316,
+				{2, 1}: 48 + 392 + 460,
+			}[[2]uint32{version, revision}]
+			if len(image) != expectedSize {
+				t.Errorf("version %d revision %d: expected %d bytes, got %d", version, revision, expectedSize, len(image))
+			}
+
+			decoder := hwinfoshmem.NewDecoder(image)
+			decoder.Codepage = bytesutil.Codepage1252
+
+			info, err := decoder.GetHeader()
+			if err != nil {
+				t.Fatalf("version %d revision %d: failed to get header: %v", version, revision, err)
+			}
+
+			decoded, err := hwinfoshmem.NewSnapshot(decoder, info)
+			if err != nil {
+				t.Fatalf("version %d revision %d: failed to decode: %v", version, revision, err)
+			}
+
+			reading := decoded.Readings[0]
+			if reading.Sensor.SensorName != "Processor" || reading.UserLabel != "CPU" || reading.Unit != "°C" || reading.Value != 47.25 {
+				t.Errorf("version %d revision %d: unexpected reading %+v", version, revision, reading)
+			}
+
+			if !decoded.Active || !decoded.LastUpdate.Equal(snapshot.LastUpdate) {
+				t.Errorf("version %d revision %d: unexpected header values", version, revision)
+			}
+		}
+	}
+}
@@ -0,0 +1,103 @@
+//go:build unix
+
+package hwinfoshmem
+
+import (
+	"errors"
+	"fmt"
+	"golang.org/x/sys/unix"
+	"os"
+	"time"
+)
+
+// fileLockRetryInterval is the time between attempts to acquire a file lock.
+const fileLockRetryInterval = 5 * time.Millisecond
+
+// FileLocker is a [Locker] that uses an advisory lock (flock) on a file.
+// It allows coordinating with a producer process that updates a file containing HWiNFO's data in
+// place, e.g. a file read using [FileReader].
+//
+// Readers acquire a shared lock which allows multiple readers at the same time.
+// The producer acquires an exclusive lock by setting [FileLocker.Exclusive] and should hold it
+// while writing.
+// Advisory locks only work when all processes accessing the file use them.
+//
+// FileLocker has an initializer function, [NewFileLocker].
+type FileLocker struct {
+	// Path of the file to lock.
+	Path string
+
+	// Exclusive makes Lock acquire an exclusive lock instead of a shared lock.
+	Exclusive bool
+
+	// Timeout is the maximum time to wait for the lock.
+	Timeout time.Duration
+
+	file *os.File
+}
+
+func NewFileLocker(path string) *FileLocker {
+	return &FileLocker{
+		Path:    path,
+		Timeout: 200 * time.Millisecond,
+	}
+}
+
+// Lock acquires the file lock.
+// Release it using [FileLocker.ReleaseLock].
+func (locker *FileLocker) Lock() error {
+	if locker.file != nil {
+		return nil
+	}
+
+	file, err := os.Open(locker.Path)
+	if err != nil {
+		return fmt.Errorf("error opening lock file (%s): %w", locker.Path, err)
+	}
+
+	how := unix.LOCK_SH
+	if locker.Exclusive {
+		how = unix.LOCK_EX
+	}
+
+	deadline := time.Now().Add(locker.Timeout)
+	for {
+		err = unix.Flock(int(file.Fd()), how|unix.LOCK_NB)
+		if err == nil || !errors.Is(err, unix.EWOULDBLOCK) || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(fileLockRetryInterval)
+	}
+
+	if err != nil {
+		_ = file.Close()
+		return fmt.Errorf("error acquiring lock on file (%s): %w", locker.Path, err)
+	}
+	locker.file = file
+
+	return nil
+}
+
+// ReleaseLock releases the file lock.
+// Returns nil when: the lock is successfully released or the lock was not held.
+func (locker *FileLocker) ReleaseLock() error {
+	if locker.file == nil {
+		return nil
+	}
+
+	err := errors.Join(
+		unix.Flock(int(locker.file.Fd()), unix.LOCK_UN),
+		locker.file.Close(),
+	)
+	locker.file = nil
+	if err != nil {
+		return fmt.Errorf("error releasing lock on file (%s): %w", locker.Path, err)
+	}
+
+	return nil
+}
+
+// IsLocked reports whether the file lock is held.
+func (locker *FileLocker) IsLocked() bool {
+	return locker.file != nil
+}
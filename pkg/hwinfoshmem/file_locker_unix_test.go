@@ -0,0 +1,68 @@
+//go:build unix
+
+package hwinfoshmem_test
+
+import (
+	"errors"
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/hwinfoshmem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileLocker(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "HWiNFO_SENS_SM2")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	producer := hwinfoshmem.NewFileLocker(path)
+	producer.Exclusive = true
+	if err := producer.Lock(); err != nil {
+		t.Fatalf("failed to acquire exclusive lock: %v", err)
+	}
+
+	reader := hwinfoshmem.NewFileReader(path)
+	reader.Locker = hwinfoshmem.NewFileLocker(path)
+	reader.Locker.(*hwinfoshmem.FileLocker).Timeout = 20 * time.Millisecond
+	if err := reader.Open(); err != nil {
+		t.Fatalf("failed to open: %v", err)
+	}
+	defer reader.Close()
+
+	if _, err := reader.GetHeader(); !errors.Is(err, hwinfoshmem.ErrNotLocked) {
+		t.Errorf("expected ErrNotLocked, got %v", err)
+	}
+
+	if err := reader.Lock(); err == nil {
+		t.Errorf("expected shared lock to fail while the exclusive lock is held")
+	}
+
+	if err := producer.ReleaseLock(); err != nil {
+		t.Fatalf("failed to release exclusive lock: %v", err)
+	}
+
+	if err := reader.Lock(); err != nil {
+		t.Fatalf("failed to acquire shared lock: %v", err)
+	}
+
+	// Multiple readers can hold the lock at the same time.
+	otherReader := hwinfoshmem.NewFileLocker(path)
+	if err := otherReader.Lock(); err != nil {
+		t.Errorf("failed to acquire second shared lock: %v", err)
+	}
+	_ = otherReader.ReleaseLock()
+
+	if _, err := reader.GetHeader(); err != nil {
+		t.Errorf("failed to get header while holding the lock: %v", err)
+	}
+
+	if err := reader.ReleaseLock(); err != nil {
+		t.Errorf("failed to release shared lock: %v", err)
+	}
+
+	if reader.IsLocked() {
+		t.Errorf("expected lock to be released")
+	}
+}
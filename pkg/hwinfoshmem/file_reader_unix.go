@@ -0,0 +1,191 @@
+//go:build unix
+
+package hwinfoshmem
+
+import (
+	"errors"
+	"fmt"
+	"golang.org/x/sys/unix"
+	"os"
+	"unsafe"
+)
+
+// FileReader allows for reading a file that contains HWiNFO's shared memory by mapping it into
+// memory read-only.
+// The file can be a dump made using [MemoryReader.Copy] or a shared memory segment in /dev/shm
+// that is kept up to date by another process, e.g. a relay of a Windows machine or HWiNFO running
+// under Wine.
+// Create an instance using NewFileReader.
+// Use the [FileReader.Open] function to make FileReader ready to start reading.
+//
+// # Changing files
+//
+// When the size of the file has changed, the file is mapped again by [FileReader.GetHeader].
+// Any results from a previous GetHeader, GetSensors, and GetReadings call should then no longer be
+// used as they point to memory that is no longer mapped.
+// Files should only be changed in place or grow, reading a part of the mapping that is no
+// longer backed by the file because it shrunk results in a SIGBUS.
+//
+// # Locking
+//
+// When the file is updated by a producer process, set [FileReader.Locker], e.g. to a [FileLocker]
+// for the same file, to prevent the producer from changing the file while it is being read.
+// When a Locker is set, the read functions will enforce locking, see [MemoryReader].
+type FileReader struct {
+	// Path of the file to read.
+	Path string
+
+	// Locker that is acquired by [FileReader.Lock]. When nil, no locking is performed.
+	Locker Locker
+
+	file *os.File
+	data []byte
+	*Reader
+}
+
+var _ Backend = (*FileReader)(nil)
+
+func NewFileReader(path string) *FileReader {
+	fileReader := &FileReader{
+		Path: path,
+	}
+	fileReader.Reader = &Reader{
+		GetPointer: func() (uintptr, error) {
+			if fileReader.file == nil {
+				return 0, errors.New("file not open, use Open first")
+			}
+
+			if err := enforceLock(fileReader.Locker); err != nil {
+				return 0, err
+			}
+
+			if len(fileReader.data) == 0 {
+				return 0, fmt.Errorf("%w: file %s is empty", ErrTruncated, fileReader.Path)
+			}
+
+			return uintptr(unsafe.Pointer(&fileReader.data[0])), nil
+		},
+		GetSize: func() (uintptr, error) {
+			return uintptr(len(fileReader.data)), nil
+		},
+	}
+
+	return fileReader
+}
+
+// Open opens and maps the file.
+// Use [FileReader.Close] when there will be no more reads.
+func (reader *FileReader) Open() error {
+	file, err := os.Open(reader.Path)
+	if err != nil {
+		return fmt.Errorf("error opening file: %w", err)
+	}
+	reader.file = file
+
+	if err = reader.remap(); err != nil {
+		defer reader.Close()
+		return err
+	}
+
+	return nil
+}
+
+// Close releases the lock, unmaps, and closes the file.
+// Call [FileReader.Open] before performing any further operations.
+func (reader *FileReader) Close() error {
+	err := errors.Join(reader.ReleaseLock(), reader.unmap())
+
+	if reader.file != nil {
+		err = errors.Join(err, reader.file.Close())
+		reader.file = nil
+	}
+
+	return err
+}
+
+// Lock acquires the lock of [FileReader.Locker]. Does nothing when no Locker is set.
+// Release it using [FileReader.ReleaseLock].
+func (reader *FileReader) Lock() error {
+	if reader.Locker == nil {
+		return nil
+	}
+
+	return reader.Locker.Lock()
+}
+
+// ReleaseLock releases the lock of [FileReader.Locker].
+// Returns nil when: the lock is successfully released, the lock was not held, or no Locker is set.
+//
+// After releasing the lock, any results from GetSensors and GetReadings should no longer be used
+// as the producer could have changed the file.
+func (reader *FileReader) ReleaseLock() error {
+	if reader.Locker == nil {
+		return nil
+	}
+
+	return reader.Locker.ReleaseLock()
+}
+
+// IsLocked reports whether the lock of [FileReader.Locker] is held.
+// Returns false when no Locker is set.
+func (reader *FileReader) IsLocked() bool {
+	return reader.Locker != nil && reader.Locker.IsLocked()
+}
+
+// GetHeader maps the file again if its size has changed and returns the header.
+// See [Reader.GetHeader].
+func (reader *FileReader) GetHeader() (*HwinfoHeader, error) {
+	if reader.file != nil {
+		if err := reader.remap(); err != nil {
+			return nil, err
+		}
+	}
+
+	return reader.Reader.GetHeader()
+}
+
+// remap maps the file if its size differs from the current mapping.
+func (reader *FileReader) remap() error {
+	info, err := reader.file.Stat()
+	if err != nil {
+		return fmt.Errorf("error getting file size: %w", err)
+	}
+
+	size := info.Size()
+	if size == int64(len(reader.data)) {
+		return nil
+	}
+
+	if err = reader.unmap(); err != nil {
+		return err
+	}
+
+	if size == 0 {
+		return nil
+	}
+
+	if int64(int(size)) != size {
+		return fmt.Errorf("file of %d bytes is too large to map", size)
+	}
+
+	data, err := unix.Mmap(int(reader.file.Fd()), 0, int(size), unix.PROT_READ, unix.MAP_SHARED)
+	if err != nil {
+		return fmt.Errorf("error mapping file: %w", err)
+	}
+	reader.data = data
+
+	return nil
+}
+
+func (reader *FileReader) unmap() error {
+	if reader.data == nil {
+		return nil
+	}
+
+	if err := unix.Munmap(reader.data); err != nil {
+		return fmt.Errorf("error unmapping file: %w", err)
+	}
+	reader.data = nil
+
+	return nil
+}
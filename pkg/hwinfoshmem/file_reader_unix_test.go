@@ -0,0 +1,78 @@
+//go:build unix
+
+package hwinfoshmem_test
+
+import (
+	"errors"
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/hwinfoshmem"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFileReader(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "HWiNFO_SENS_SM2")
+	if err := os.WriteFile(path, data[:48], 0o644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	reader := hwinfoshmem.NewFileReader(path)
+	if _, err := reader.GetHeader(); err == nil {
+		t.Errorf("expected error when reading before Open")
+	}
+
+	if err := reader.Open(); err != nil {
+		t.Fatalf("failed to open: %v", err)
+	}
+	defer reader.Close()
+
+	info, err := reader.GetHeader()
+	if err != nil {
+		t.Fatalf("failed to get header: %v", err)
+	}
+
+	if _, err = reader.GetReadings(info); !errors.Is(err, hwinfoshmem.ErrTruncated) {
+		t.Errorf("expected ErrTruncated for a file containing only the header, got %v", err)
+	}
+
+	// The producer writes the rest of the data, growing the file.
+	file, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatalf("failed to open file for writing: %v", err)
+	}
+	if _, err = file.WriteAt(data[48:], 48); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	if err = file.Close(); err != nil {
+		t.Fatalf("failed to close file: %v", err)
+	}
+
+	_, sensors, readings := readAll(t, hwinfoshmem.NewBytesReader(data))
+	_, fileSensors, fileReadings := readAll(t, reader)
+
+	if !reflect.DeepEqual(sensors, fileSensors) {
+		t.Errorf("sensors differ")
+	}
+
+	if !reflect.DeepEqual(readings, fileReadings) {
+		t.Errorf("readings differ")
+	}
+}
+
+func TestFileReaderEmptyFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "empty.bin")
+	if err := os.WriteFile(path, nil, 0o644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	reader := hwinfoshmem.NewFileReader(path)
+	if err := reader.Open(); err != nil {
+		t.Fatalf("failed to open: %v", err)
+	}
+	defer reader.Close()
+
+	if _, err := reader.GetHeader(); !errors.Is(err, hwinfoshmem.ErrTruncated) {
+		t.Errorf("expected ErrTruncated, got %v", err)
+	}
+}
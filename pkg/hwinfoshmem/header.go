@@ -20,7 +20,7 @@ type HwinfoHeader struct {
 
 	// Options:
 	//  - 0: Initial layout (HWiNFO ver <= 6.11)
-	//  - 1: Added (HWiNFO v6.11-3917)
+	//  - 1: Added PollingPeriodInMs (HWiNFO v6.11-3917)
 	Revision uint32
 
 	// The unix time (seconds since 1970-01-01) when the last update to the data occurred.
@@ -46,14 +46,29 @@ type HwinfoHeader struct {
 	ReadingAmount uint32
 
 	// Time in milliseconds between updates of the data by HWiNFO.
+	// Added in revision 1 of the layout, see [LayoutInfo].
 	PollingPeriodInMs uint32
 }
 
+var (
+	statusActive   = [4]byte{0x48, 0x57, 0x69, 0x53} // HWiS in ASCII
+	statusInactive = [4]byte{0x44, 0x41, 0x45, 0x44} // DAED in ASCII
+)
+
 // IsActive returns true when HWiNFO is currently updating the shared memory.
 // When HWiNFO shared memory is not active, this usually means that the shared memory time limit
 // has expired.
 func (info HwinfoHeader) IsActive() bool {
-	return info.Status == [4]byte{0x48, 0x57, 0x69, 0x53} // HWiS in ASCII
+	return info.Status == statusActive
+}
+
+// SetActive sets the status to "HWiS" when active is true and to "DAED" otherwise.
+func (info *HwinfoHeader) SetActive(active bool) {
+	if active {
+		info.Status = statusActive
+	} else {
+		info.Status = statusInactive
+	}
 }
 
 // GetStatus returns the status of the shared memory.
@@ -76,3 +91,9 @@ func (info HwinfoHeader) GetLastUpdate() int64 {
 func (info HwinfoHeader) GetLastUpdateTime() time.Time {
 	return time.Unix(info.GetLastUpdate(), 0)
 }
+
+// SetLastUpdateTime sets the time when the last update to the data occurred.
+// The time is stored in seconds, anything smaller is discarded.
+func (info *HwinfoHeader) SetLastUpdateTime(lastUpdate time.Time) {
+	binary.LittleEndian.PutUint64(info.LastUpdate[:], uint64(lastUpdate.Unix()))
+}
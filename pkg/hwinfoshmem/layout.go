@@ -0,0 +1,104 @@
+package hwinfoshmem
+
+import "fmt"
+
+// Sizes of the elements in the historic layouts.
+const (
+	// headerSizeRevision0 is the size of the header before PollingPeriodInMs was added.
+	headerSizeRevision0 = 44
+
+	// sensorSizeVersion1 is the size of a sensor before SensorName was added.
+	sensorSizeVersion1 = 264
+
+	// readingSizeVersion1 is the size of a reading before UserLabel and Unit were added.
+	readingSizeVersion1 = 316
+)
+
+// LayoutInfo describes the layout of HWiNFO's shared memory and which fields of [HwinfoHeader],
+// [HwinfoSensor], and [HwinfoReading] contain valid data.
+//
+// HWiNFO has extended the layout over time:
+//   - Version 1, revision 0: the initial layout.
+//   - Revision 1 (HWiNFO v6.11-3917): added [HwinfoHeader.PollingPeriodInMs].
+//   - Version 2 (HWiNFO v7.33): added the UTF-8 strings [HwinfoSensor.SensorName],
+//     [HwinfoReading.UserLabel], and [HwinfoReading.Unit].
+//
+// [Reader] converts the memory in place and therefore only supports the newest layout.
+// [Decoder] supports all layouts and, when the UTF-8 strings are absent, fills them using the
+// ASCII fields.
+//
+// Get the layout of a header using [DetectLayout].
+type LayoutInfo struct {
+	// Structure layout version as reported by the header.
+	Version uint32
+
+	// Structure layout revision as reported by the header.
+	Revision uint32
+
+	// The amount of bytes in use by the header.
+	HeaderSize uint32
+
+	// The size of each sensor's data in bytes as reported by the header. Can be larger than the
+	// known fields when a newer HWiNFO version added fields.
+	SensorSize uint32
+
+	// The size of each reading's data in bytes as reported by the header. Can be larger than the
+	// known fields when a newer HWiNFO version added fields.
+	ReadingSize uint32
+
+	// Whether [HwinfoHeader.PollingPeriodInMs] is valid.
+	HasPollingPeriod bool
+
+	// Whether [HwinfoSensor.SensorName], [HwinfoReading.UserLabel], and [HwinfoReading.Unit] are
+	// present in the data.
+	HasUtf8Strings bool
+}
+
+// DetectLayout determines the layout of the data described by the header using the version,
+// revision, and element sizes.
+// A header claiming version 2 whose element sizes are too small to contain the UTF-8 strings is
+// treated as version 1.
+//
+// Returns an error wrapping [ErrUnsupportedSize] when the element sizes are smaller than the
+// initial layout.
+func DetectLayout(info *HwinfoHeader) (LayoutInfo, error) {
+	layout := LayoutInfo{
+		Version:          info.Version,
+		Revision:         info.Revision,
+		HeaderSize:       headerSizeRevision0,
+		SensorSize:       info.SensorSize,
+		ReadingSize:      info.ReadingSize,
+		HasPollingPeriod: info.Revision >= 1,
+	}
+
+	if layout.HasPollingPeriod {
+		layout.HeaderSize = uint32(headerSize)
+	}
+
+	sensorsFit := info.SensorAmount == 0 || uint64(info.SensorSize) >= sensorSize
+	readingsFit := info.ReadingAmount == 0 || uint64(info.ReadingSize) >= readingSize
+	layout.HasUtf8Strings = info.Version >= 2 && sensorsFit && readingsFit
+
+	if err := validateLayout(info, layout.minimumSizes()); err != nil {
+		return layout, fmt.Errorf("error detecting layout: %w", err)
+	}
+
+	return layout, nil
+}
+
+// minimumSizes returns the sizes that the elements need to have to contain the fields of the
+// layout.
+func (layout LayoutInfo) minimumSizes() elementSizes {
+	sizes := elementSizes{
+		header:  uint64(layout.HeaderSize),
+		sensor:  sensorSizeVersion1,
+		reading: readingSizeVersion1,
+	}
+
+	if layout.HasUtf8Strings {
+		sizes.sensor = sensorSize
+		sizes.reading = readingSize
+	}
+
+	return sizes
+}
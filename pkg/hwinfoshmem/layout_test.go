@@ -0,0 +1,223 @@
+package hwinfoshmem_test
+
+import (
+	"encoding/binary"
+	"errors"
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/hwinfoshmem"
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/util/bytesutil"
+	"math"
+	"testing"
+	"time"
+)
+
+type syntheticLayout struct {
+	version     uint32
+	revision    uint32
+	headerSize  uint32
+	sensorSize  uint32
+	readingSize uint32
+}
+
+// buildSyntheticImage creates shared memory containing one sensor with one reading using the
+// given layout. Fields that do not exist in the layout are not written.
+func buildSyntheticImage(layout syntheticLayout) []byte {
+	le := binary.LittleEndian
+	sensorOffset := layout.headerSize
+	readingOffset := sensorOffset + layout.sensorSize
+	image := make([]byte, readingOffset+layout.readingSize)
+
+	copy(image, "HWiS")
+	le.PutUint32(image[4:], layout.version)
+	le.PutUint32(image[8:], layout.revision)
+	le.PutUint64(image[12:], 1694966200)
+	le.PutUint32(image[20:], sensorOffset)
+	le.PutUint32(image[24:], layout.sensorSize)
+	le.PutUint32(image[28:], 1)
+	le.PutUint32(image[32:], readingOffset)
+	le.PutUint32(image[36:], layout.readingSize)
+	le.PutUint32(image[40:], 1)
+	if layout.headerSize >= 48 {
+		le.PutUint32(image[44:], 2000)
+	}
+
+	sensor := image[sensorOffset:]
+	le.PutUint32(sensor[0:], 0xF0000300)
+	le.PutUint32(sensor[4:], 0)
+	copy(sensor[8:], "CPU [#0]: AMD Ryzen 9 7950X")
+	copy(sensor[136:], "CPU [#0]: Processor")
+	if layout.sensorSize >= 392 {
+		copy(sensor[264:], "CPU [#0]: Processor UTF-8")
+	}
+
+	reading := image[readingOffset:]
+	le.PutUint32(reading[0:], uint32(hwinfoshmem.SENSOR_TYPE_TEMP))
+	le.PutUint32(reading[4:], 0)
+	le.PutUint32(reading[8:], 0x1000000)
+	copy(reading[12:], "CPU (Tctl/Tdie)")
+	copy(reading[140:], "CPU")
+	copy(reading[268:], "\xb0C") // °C in Windows-1252
+	le.PutUint64(reading[284:], math.Float64bits(47.25))
+	le.PutUint64(reading[292:], math.Float64bits(40))
+	le.PutUint64(reading[300:], math.Float64bits(62))
+	le.PutUint64(reading[308:], math.Float64bits(47.5))
+	if layout.readingSize >= 460 {
+		copy(reading[316:], "CPU UTF-8")
+		copy(reading[444:], "°C UTF-8")
+	}
+
+	return image
+}
+
+func TestDecoderHistoricLayouts(t *testing.T) {
+	tests := []struct {
+		name             string
+		layout           syntheticLayout
+		hasPollingPeriod bool
+		hasUtf8Strings   bool
+	}{
+		{"version 1 revision 0", syntheticLayout{1, 0, 44, 264, 316}, false, false},
+		{"version 1 revision 1", syntheticLayout{1, 1, 48, 264, 316}, true, false},
+		{"version 2 revision 1", syntheticLayout{2, 1, 48, 392, 460}, true, true},
+		{"version 2 with small elements", syntheticLayout{2, 1, 48, 264, 316}, true, false},
+		{"version 1 with large elements", syntheticLayout{1, 1, 48, 392, 460}, true, false},
+		{"newer version with extra fields", syntheticLayout{3, 2, 64, 400, 500}, true, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			decoder := hwinfoshmem.NewDecoder(buildSyntheticImage(test.layout))
+			decoder.Codepage = bytesutil.Codepage1252
+
+			info, err := decoder.GetHeader()
+			if err != nil {
+				t.Fatalf("failed to get header: %v", err)
+			}
+
+			layout, err := decoder.GetLayout(info)
+			if err != nil {
+				t.Fatalf("failed to get layout: %v", err)
+			}
+
+			if layout.HasPollingPeriod != test.hasPollingPeriod {
+				t.Errorf("expected HasPollingPeriod %v", test.hasPollingPeriod)
+			}
+
+			if layout.HasUtf8Strings != test.hasUtf8Strings {
+				t.Errorf("expected HasUtf8Strings %v", test.hasUtf8Strings)
+			}
+
+			snapshot, err := hwinfoshmem.NewSnapshot(decoder, info)
+			if err != nil {
+				t.Fatalf("failed to create snapshot: %v", err)
+			}
+
+			expectedPollingPeriod := time.Duration(0)
+			if test.hasPollingPeriod {
+				expectedPollingPeriod = 2 * time.Second
+			}
+			if snapshot.PollingPeriod != expectedPollingPeriod {
+				t.Errorf("expected polling period %v, got %v", expectedPollingPeriod, snapshot.PollingPeriod)
+			}
+
+			expectedName, expectedLabel, expectedUnit := "CPU [#0]: Processor", "CPU", "°C"
+			if test.hasUtf8Strings {
+				expectedName, expectedLabel, expectedUnit = "CPU [#0]: Processor UTF-8", "CPU UTF-8", "°C UTF-8"
+			}
+
+			sensors, err := decoder.GetSensors(info)
+			if err != nil {
+				t.Fatalf("failed to get sensors: %v", err)
+			}
+
+			readings, err := decoder.GetReadings(info)
+			if err != nil {
+				t.Fatalf("failed to get readings: %v", err)
+			}
+
+			for _, name := range []string{sensors[0].SensorName.String(), snapshot.Sensors[0].SensorName} {
+				if name != expectedName {
+					t.Errorf("expected sensor name %q, got %q", expectedName, name)
+				}
+			}
+
+			for _, label := range []string{readings[0].UserLabel.String(), snapshot.Readings[0].UserLabel} {
+				if label != expectedLabel {
+					t.Errorf("expected label %q, got %q", expectedLabel, label)
+				}
+			}
+
+			for _, unit := range []string{readings[0].Unit.String(), snapshot.Readings[0].Unit} {
+				if unit != expectedUnit {
+					t.Errorf("expected unit %q, got %q", expectedUnit, unit)
+				}
+			}
+
+			reading := snapshot.Readings[0]
+			if reading.Value != 47.25 || reading.ValueMin != 40 || reading.ValueMax != 62 || reading.ValueAvg != 47.5 {
+				t.Errorf("unexpected values %+v", reading)
+			}
+
+			if reading.OriginalLabel != "CPU (Tctl/Tdie)" {
+				t.Errorf("unexpected original label %q", reading.OriginalLabel)
+			}
+		})
+	}
+}
+
+func TestDetectLayoutUnsupported(t *testing.T) {
+	image := buildSyntheticImage(syntheticLayout{1, 1, 48, 200, 316})
+	info, err := hwinfoshmem.NewDecoder(image).GetHeader()
+	if err != nil {
+		t.Fatalf("failed to get header: %v", err)
+	}
+
+	if _, err = hwinfoshmem.DetectLayout(info); !errors.Is(err, hwinfoshmem.ErrUnsupportedSize) {
+		t.Errorf("expected ErrUnsupportedSize, got %v", err)
+	}
+}
+
+func TestReaderRejectsHistoricLayout(t *testing.T) {
+	reader := hwinfoshmem.NewBytesReader(buildSyntheticImage(syntheticLayout{1, 1, 48, 264, 316}))
+	info, err := reader.GetHeader()
+	if err != nil {
+		t.Fatalf("failed to get header: %v", err)
+	}
+
+	if _, err = reader.GetReadings(info); !errors.Is(err, hwinfoshmem.ErrUnsupportedSize) {
+		t.Errorf("expected ErrUnsupportedSize, got %v", err)
+	}
+}
+
+func TestDecoderCodepage(t *testing.T) {
+	image := buildSyntheticImage(syntheticLayout{1, 1, 48, 264, 316})
+	copy(image[48+136:], "\xd6\xe5\xed\xf2\xf0\xe0\xeb\xfc\xed\xfb\xe9 \xef\xf0\xee\xf6\xe5\xf1\xf1\xee\xf0\x00")
+	decoder := hwinfoshmem.NewDecoder(image)
+	decoder.Codepage = bytesutil.Codepage1251
+
+	info, err := decoder.GetHeader()
+	if err != nil {
+		t.Fatalf("failed to get header: %v", err)
+	}
+
+	snapshot, err := hwinfoshmem.NewSnapshot(decoder, info)
+	if err != nil {
+		t.Fatalf("failed to create snapshot: %v", err)
+	}
+
+	if name := snapshot.Sensors[0].SensorName; name != "Центральный процессор" {
+		t.Errorf("unexpected sensor name %q", name)
+	}
+
+	if name := snapshot.Sensors[0].SensorNameOriginal; name != "CPU [#0]: AMD Ryzen 9 7950X" {
+		t.Errorf("unexpected original sensor name %q", name)
+	}
+
+	if unit := snapshot.Readings[0].Unit; unit != "°C" {
+		t.Errorf("unexpected unit %q", unit)
+	}
+
+	decoder.Codepage = 12345
+	if _, err = hwinfoshmem.NewSnapshot(decoder, info); !errors.Is(err, bytesutil.ErrUnsupportedCodepage) {
+		t.Errorf("expected ErrUnsupportedCodepage, got %v", err)
+	}
+}
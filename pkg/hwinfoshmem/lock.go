@@ -0,0 +1,33 @@
+package hwinfoshmem
+
+import "errors"
+
+// ErrNotLocked is returned when reading while lock enforcement is enabled and the lock is not held.
+var ErrNotLocked = errors.New("lock not acquired. Acquire it using Lock()")
+
+// Locker prevents the producer of HWiNFO's data from changing it while it is being read.
+//
+// Implementations:
+//   - [MutexLocker]: HWiNFO's named mutex, used by [MemoryReader]. Windows only.
+//   - [FileLocker]: advisory file lock for files written by a producer process. Unix only.
+type Locker interface {
+	// Lock acquires the lock.
+	// While holding the lock, the producer will pause so keep this lock as short as possible.
+	Lock() error
+
+	// ReleaseLock releases the lock.
+	// Returns nil when: the lock is successfully released or the lock was not held.
+	ReleaseLock() error
+
+	// IsLocked reports whether the lock is currently held.
+	IsLocked() bool
+}
+
+// enforceLock returns ErrNotLocked when a locker is given and its lock is not held.
+func enforceLock(locker Locker) error {
+	if locker == nil || locker.IsLocked() {
+		return nil
+	}
+
+	return ErrNotLocked
+}
@@ -20,15 +20,18 @@ const (
 // # Locking
 //
 // Locking is required to prevent HWiNFO from changing the shared memory while it is being read.
-// Locking is performed by using the Lock function.
+// Locking is performed by using the Lock function which, by default, acquires HWiNFO's mutex using
+// a [MutexLocker]. A different [Locker] can be used by setting [MemoryReader.Locker].
 // While the lock is held, HWiNFO will pause any updates to the shared memory and its own UI.
 // Therefore, locks should be released as soon as possible.
 //
 // By default, the read functions will enforce locking.
 // If necessary, it is possible to disable this enforcement by setting
-// [MemoryReader.DisableLockEnforcement] to `false`.
+// [MemoryReader.DisableLockEnforcement] to `true`.
 // Do note that this causes the risk of receiving garbage data when HWiNFO changes the shared memory
 // layout.
+// To read without the lock, use [Reader.CopyConsistent] which detects and retries copies during
+// which HWiNFO changed the shared memory.
 //
 // # Quick processing
 //
@@ -51,16 +54,30 @@ const (
 //  4. [MemoryReader.Copy]
 //  5. [MemoryReader.ReleaseLock]
 //  6. Process the data, this will not block HWiNFO
+//
+// # Continuous reading
+//
+// To process the data every time HWiNFO updates it, use a [Watcher] which performs the steps
+// above and sends a [Snapshot] on a channel.
 type MemoryReader struct {
 	DisableLockEnforcement bool
-	mutex                  windows.Handle
-	mmfHandle              windows.Handle
-	mmfPtr                 uintptr
+
+	// Locker that is acquired by [MemoryReader.Lock], HWiNFO's mutex by default. When nil, no
+	// locking is performed.
+	Locker Locker
+
+	mmfHandle windows.Handle
+	mmfPtr    uintptr
+	mmfSize   uintptr
 	*Reader
 }
 
+var _ Backend = (*MemoryReader)(nil)
+
 func NewMemoryReader() *MemoryReader {
-	memoryReader := &MemoryReader{}
+	memoryReader := &MemoryReader{
+		Locker: NewHwinfoMutexLocker(),
+	}
 	memoryReader.Reader = &Reader{
 		GetPointer: func() (uintptr, error) {
 			if memoryReader.mmfPtr == 0 {
@@ -73,6 +90,9 @@ func NewMemoryReader() *MemoryReader {
 
 			return memoryReader.mmfPtr, nil
 		},
+		GetSize: func() (uintptr, error) {
+			return memoryReader.mmfSize, nil
+		},
 	}
 	return memoryReader
 }
@@ -112,6 +132,14 @@ func (reader *MemoryReader) Open() error {
 	}
 	reader.mmfPtr = mmfPtr
 
+	var memoryInfo windows.MemoryBasicInformation
+	err = windows.VirtualQuery(mmfPtr, &memoryInfo, unsafe.Sizeof(memoryInfo))
+	if err != nil {
+		defer reader.Close()
+		return fmt.Errorf("error querying size of mapped view: %w", err)
+	}
+	reader.mmfSize = memoryInfo.RegionSize
+
 	return nil
 }
 
@@ -136,43 +164,35 @@ func (reader *MemoryReader) Copy(info *HwinfoHeader) *BytesReader {
 // While holding the mutex, HWiNFO will pause so keep this lock as short as possible.
 // Locks should be held until data is processed or copied.
 // Release it using [MemoryReader.ReleaseLock].
+// Does nothing when no Locker is set.
 func (reader *MemoryReader) Lock() error {
-	mutex, err := openMutex(hwinfoMutexName)
-	if err != nil {
-		return fmt.Errorf("error opening HWiNFO mutex (%s): %w", hwinfoMutexName, err)
-	}
-	reader.mutex = mutex
-
-	_, err = windows.WaitForSingleObject(mutex, 200)
-	if err != nil {
-		return fmt.Errorf("error waiting for HWiNFO mutex (%s): %w", hwinfoMutexName, err)
+	if reader.Locker == nil {
+		return nil
 	}
 
-	return nil
+	return reader.Locker.Lock()
 }
 
 // ReleaseLock releases the HWiNFO mutex.
-// Returns nil when: the mutex is successfully released or the mutex was not held.
+// Returns nil when: the mutex is successfully released, the mutex was not held, or no Locker is
+// set.
 //
 // After releasing the lock, any results from GetSensors and GetReadings should no longer be used
 // as HWiNFO could have changed the memory layout which can turn the results into garbage.
 // E.g. HWiNFO could have since detected a new sensor which might cause part of the data to be
-// offset.
-//
-// Errors returned are documented at
-// https://learn.microsoft.com/en-us/windows/win32/api/synchapi/nf-synchapi-releasemutex.
+// offset. Use [DiffTopology] to detect such changes between snapshots.
 func (reader *MemoryReader) ReleaseLock() error {
-	if reader.mutex == 0 {
+	if reader.Locker == nil {
 		return nil
 	}
 
-	err := windows.ReleaseMutex(reader.mutex)
-	reader.mutex = 0
-	if err != nil {
-		return fmt.Errorf("error releasing HWiNFO lock: %w", err)
-	}
+	return reader.Locker.ReleaseLock()
+}
 
-	return nil
+// IsLocked reports whether the HWiNFO mutex is held.
+// Returns false when no Locker is set.
+func (reader *MemoryReader) IsLocked() bool {
+	return reader.Locker != nil && reader.Locker.IsLocked()
 }
 
 func (reader *MemoryReader) closeMappedFileHandle() error {
@@ -192,6 +212,7 @@ func (reader *MemoryReader) closeMapView() error {
 			return fmt.Errorf("error unmapping MemoryReader view of file: %w", err)
 		}
 		reader.mmfPtr = 0
+		reader.mmfSize = 0
 	}
 
 	return nil
@@ -202,9 +223,5 @@ func (reader *MemoryReader) enforceLock() error {
 		return nil
 	}
 
-	if reader.mutex != 0 {
-		return nil
-	}
-
-	return errors.New("lock not acquired. Acquire it using Lock()")
+	return enforceLock(reader.Locker)
 }
@@ -1,9 +1,82 @@
 package hwinfoshmem
 
 import (
+	"fmt"
 	"golang.org/x/sys/windows"
+	"time"
 )
 
+// MutexLocker is a [Locker] that uses a Windows named mutex.
+//
+// MutexLocker has an initializer function for HWiNFO's mutex, [NewHwinfoMutexLocker].
+type MutexLocker struct {
+	// Name of the mutex.
+	Name string
+
+	// Timeout is the maximum time to wait for the mutex.
+	Timeout time.Duration
+
+	mutex windows.Handle
+}
+
+// NewHwinfoMutexLocker returns a MutexLocker for the mutex HWiNFO uses to guard its shared memory.
+func NewHwinfoMutexLocker() *MutexLocker {
+	return &MutexLocker{
+		Name:    hwinfoMutexName,
+		Timeout: 200 * time.Millisecond,
+	}
+}
+
+// Lock acquires the mutex.
+// Release it using [MutexLocker.ReleaseLock].
+func (locker *MutexLocker) Lock() error {
+	if locker.mutex != 0 {
+		return nil
+	}
+
+	mutex, err := openMutex(locker.Name)
+	if err != nil {
+		return fmt.Errorf("error opening mutex (%s): %w", locker.Name, err)
+	}
+
+	event, err := windows.WaitForSingleObject(mutex, uint32(locker.Timeout.Milliseconds()))
+	if err == nil && event == uint32(windows.WAIT_TIMEOUT) {
+		err = windows.WAIT_TIMEOUT
+	}
+	if err != nil {
+		_ = windows.CloseHandle(mutex)
+		return fmt.Errorf("error waiting for mutex (%s): %w", locker.Name, err)
+	}
+	locker.mutex = mutex
+
+	return nil
+}
+
+// ReleaseLock releases the mutex.
+// Returns nil when: the mutex is successfully released or the mutex was not held.
+//
+// Errors returned are documented at
+// https://learn.microsoft.com/en-us/windows/win32/api/synchapi/nf-synchapi-releasemutex.
+func (locker *MutexLocker) ReleaseLock() error {
+	if locker.mutex == 0 {
+		return nil
+	}
+
+	err := windows.ReleaseMutex(locker.mutex)
+	_ = windows.CloseHandle(locker.mutex)
+	locker.mutex = 0
+	if err != nil {
+		return fmt.Errorf("error releasing mutex (%s): %w", locker.Name, err)
+	}
+
+	return nil
+}
+
+// IsLocked reports whether the mutex is held.
+func (locker *MutexLocker) IsLocked() bool {
+	return locker.mutex != 0
+}
+
 func openMutex(name string) (windows.Handle, error) {
 	nameUtf16, err := windows.UTF16PtrFromString(name)
 	if err != nil {
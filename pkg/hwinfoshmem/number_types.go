@@ -13,3 +13,10 @@ type HwinfoFloat64 [8]byte
 func (b HwinfoFloat64) ToFloat64() float64 {
 	return math.Float64frombits(binary.LittleEndian.Uint64(b[:]))
 }
+
+// NewHwinfoFloat64 converts a float64 to HwinfoFloat64.
+func NewHwinfoFloat64(value float64) HwinfoFloat64 {
+	var b HwinfoFloat64
+	binary.LittleEndian.PutUint64(b[:], math.Float64bits(value))
+	return b
+}
@@ -1,15 +1,46 @@
 package hwinfoshmem
 
-import "unsafe"
+import (
+	"fmt"
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/util/bytesutil"
+	"slices"
+	"sync"
+	"unsafe"
+)
 
 // Reader is the part that actually converts bytes to the [HwinfoSensor], [HwinfoReading], and
 // [HwinfoHeader] structs.
 // It uses the pointer supplied by GetPointer as a start point to convert the bytes into the
 // aforementioned structs.
+//
+// Before any sensor or reading is converted, the header is checked using [ValidateHeader] so that
+// a corrupt or truncated header cannot cause memory outside the data to be read.
 type Reader struct {
 	// GetPointer returns a pointer which is the start of the memory/byte array that contains
 	// HWiNFO's sensor data. If the function returns an error, it will be passed along.
 	GetPointer func() (uintptr, error)
+
+	// GetSize returns the amount of bytes that can be read starting from the pointer returned by
+	// GetPointer. If the function returns an error, it will be passed along.
+	// When GetSize is nil, the header is only checked against the known struct sizes and not
+	// against the size of the data. Always set it when reading untrusted data.
+	GetSize func() (uintptr, error)
+
+	// Codepage used to convert the ASCII strings, see [HwinfoSensorStringAscii].
+	// When zero, [SystemCodepage] is used.
+	Codepage bytesutil.Codepage
+
+	indexMutex sync.Mutex
+	index      *readingIndex
+}
+
+// GetCodepage returns the codepage used to convert the ASCII strings.
+func (reader *Reader) GetCodepage() bytesutil.Codepage {
+	if reader.Codepage == 0 {
+		return SystemCodepage()
+	}
+
+	return reader.Codepage
 }
 
 // GetHeader returns the header of the shared memory. Make sure to lock using Lock().
@@ -19,6 +50,17 @@ func (reader *Reader) GetHeader() (*HwinfoHeader, error) {
 		return nil, err
 	}
 
+	if reader.GetSize != nil {
+		size, err := reader.GetSize()
+		if err != nil {
+			return nil, err
+		}
+
+		if uint64(size) < headerSize {
+			return nil, fmt.Errorf("%w: header needs %d bytes, got %d", ErrTruncated, headerSize, size)
+		}
+	}
+
 	return (*HwinfoHeader)(unsafe.Pointer(pointer)), nil
 }
 
@@ -27,7 +69,7 @@ func (reader *Reader) GetHeader() (*HwinfoHeader, error) {
 // when calling [Reader.GetHeader] and stays held while calling this function and processing
 // its results.
 func (reader *Reader) GetSensors(info *HwinfoHeader) ([]*HwinfoSensor, error) {
-	pointer, err := reader.GetPointer()
+	pointer, err := reader.getValidatedPointer(info)
 	if err != nil {
 		return nil, err
 	}
@@ -45,7 +87,7 @@ func (reader *Reader) GetSensors(info *HwinfoHeader) ([]*HwinfoSensor, error) {
 
 // GetReadings returns all HWiNFO readings.
 func (reader *Reader) GetReadings(info *HwinfoHeader) ([]*HwinfoReading, error) {
-	pointer, err := reader.GetPointer()
+	pointer, err := reader.getValidatedPointer(info)
 	if err != nil {
 		return nil, err
 	}
@@ -61,31 +103,63 @@ func (reader *Reader) GetReadings(info *HwinfoHeader) ([]*HwinfoReading, error)
 	return readings, nil
 }
 
+// ReadingIdSensorCombo identifies a reading by the position of its sensor.
+// The position changes when HWiNFO adds, removes, or reorders sensors, use [ReadingKey] to
+// identify readings across these changes.
 type ReadingIdSensorCombo struct {
 	Id          uint32
 	SensorIndex uint32
 }
 
-// GetReadingsById returns the readings that match the given sensor index/id combinations.
+// GetReadingsById returns the readings that match the given sensor index/id combinations in the
+// order they are reported by HWiNFO.
+// The readings are found using an index that is built once and reused until the header describes
+// a different layout or a reading in the index no longer has its combination, see [ReadingLookup]
+// for lookups that do not allocate.
 func (reader *Reader) GetReadingsById(info *HwinfoHeader, readingIds []ReadingIdSensorCombo) ([]*HwinfoReading, error) {
-	readings := make([]*HwinfoReading, 0)
-	pointer, err := reader.GetPointer()
+	pointer, err := reader.getValidatedPointer(info)
 	if err != nil {
 		return nil, err
 	}
 
-	for i := uint32(0); i < info.ReadingAmount; i++ {
-		offset := pointer + uintptr(info.ReadingSectionOffset) + uintptr(i)*uintptr(info.ReadingSize)
-		sensorIndex := *(*uint32)(unsafe.Pointer(offset + uintptr(4)))
-		readingId := *(*uint32)(unsafe.Pointer(offset + uintptr(8)))
-
-		for _, indexInfo := range readingIds {
-			if indexInfo.SensorIndex == sensorIndex && indexInfo.Id == readingId {
-				reading := (*HwinfoReading)(unsafe.Pointer(offset))
-				readings = append(readings, reading)
-			}
-		}
+	index := reader.getIndex(pointer, info, nil)
+	positions, ok := index.findCombos(pointer, readingIds)
+	if !ok {
+		// The readings changed without changing the layout, scan all readings again.
+		index = reader.getIndex(pointer, info, index)
+		positions, _ = index.findCombos(pointer, readingIds)
+	}
+
+	slices.Sort(positions)
+	readings := make([]*HwinfoReading, len(positions))
+	for i, position := range positions {
+		readings[i] = index.layout.readingAt(pointer, position)
 	}
 
 	return readings, nil
 }
+
+// getValidatedPointer returns the pointer supplied by GetPointer after checking that the sections
+// described by the header can be read.
+func (reader *Reader) getValidatedPointer(info *HwinfoHeader) (uintptr, error) {
+	pointer, err := reader.GetPointer()
+	if err != nil {
+		return 0, err
+	}
+
+	if reader.GetSize == nil {
+		err = validateLayout(info, structSizes)
+	} else {
+		var size uintptr
+		size, err = reader.GetSize()
+		if err == nil {
+			err = ValidateHeader(info, uint64(size))
+		}
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	return pointer, nil
+}
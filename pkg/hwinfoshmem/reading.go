@@ -1,5 +1,8 @@
 package hwinfoshmem
 
+// ReadingType is the kind of value a reading contains.
+// Newer versions of HWiNFO could report types that are not known to this library, these are
+// represented as type(N) by [ReadingType.String].
 type ReadingType uint32
 
 const (
@@ -46,8 +49,10 @@ type HwinfoReading struct {
 	ValueAvg HwinfoFloat64
 
 	// Displayed label which might have been renamed by the user.
+	// Added in version 2 of the layout, see [LayoutInfo].
 	UserLabel HwinfoSensorStringUtf8
 
 	// The unit of the reading. E.g. °C, RPM.
+	// Added in version 2 of the layout, see [LayoutInfo].
 	Unit HwinfoUnitStringUtf8
 }
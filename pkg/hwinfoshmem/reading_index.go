@@ -0,0 +1,240 @@
+package hwinfoshmem
+
+import (
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/util/bytesutil"
+	"unsafe"
+)
+
+// sectionLayout contains the fields of the header that determine where the sensors and readings
+// are located.
+type sectionLayout struct {
+	sensorSectionOffset  uint32
+	sensorSize           uint32
+	sensorAmount         uint32
+	readingSectionOffset uint32
+	readingSize          uint32
+	readingAmount        uint32
+}
+
+func newSectionLayout(info *HwinfoHeader) sectionLayout {
+	return sectionLayout{
+		sensorSectionOffset:  info.SensorSectionOffset,
+		sensorSize:           info.SensorSize,
+		sensorAmount:         info.SensorAmount,
+		readingSectionOffset: info.ReadingSectionOffset,
+		readingSize:          info.ReadingSize,
+		readingAmount:        info.ReadingAmount,
+	}
+}
+
+// sensorAt returns the sensor at the given position. The pointer must be validated for the layout.
+func (layout sectionLayout) sensorAt(pointer uintptr, position uint32) *HwinfoSensor {
+	offset := pointer + uintptr(layout.sensorSectionOffset) + uintptr(position)*uintptr(layout.sensorSize)
+	return (*HwinfoSensor)(unsafe.Pointer(offset))
+}
+
+// readingAt returns the reading at the given position. The pointer must be validated for the
+// layout.
+func (layout sectionLayout) readingAt(pointer uintptr, position int) *HwinfoReading {
+	offset := pointer + uintptr(layout.readingSectionOffset) + uintptr(position)*uintptr(layout.readingSize)
+	return (*HwinfoReading)(unsafe.Pointer(offset))
+}
+
+// readingIndex maps the identifiers of readings to their position in the reading section.
+// It is built once per layout, see [Reader.getIndex].
+type readingIndex struct {
+	layout   sectionLayout
+	codepage bytesutil.Codepage
+
+	// byCombo contains the positions of all readings with the combination, in order.
+	byCombo map[ReadingIdSensorCombo][]int
+
+	// byKey contains the position of the first reading with the key. The keys have no original
+	// label.
+	byKey map[ReadingKey]int
+
+	// byLabel contains the position of the first reading of the sensor with the original label.
+	// The keys have a zero Id.
+	byLabel map[ReadingKey]int
+}
+
+func newReadingIndex(pointer uintptr, layout sectionLayout, codepage bytesutil.Codepage) *readingIndex {
+	index := &readingIndex{
+		layout:   layout,
+		codepage: codepage,
+		byCombo:  make(map[ReadingIdSensorCombo][]int, layout.readingAmount),
+		byKey:    make(map[ReadingKey]int, layout.readingAmount),
+		byLabel:  make(map[ReadingKey]int, layout.readingAmount),
+	}
+
+	for position := 0; position < int(layout.readingAmount); position++ {
+		reading := layout.readingAt(pointer, position)
+		combo := ReadingIdSensorCombo{Id: reading.Id, SensorIndex: reading.SensorIndex}
+		index.byCombo[combo] = append(index.byCombo[combo], position)
+
+		if reading.SensorIndex >= layout.sensorAmount {
+			continue
+		}
+
+		sensor := layout.sensorAt(pointer, reading.SensorIndex)
+		key := ReadingKey{SensorId: sensor.SensorId, SensorInstance: sensor.SensorInstance, Id: reading.Id}
+		if _, exists := index.byKey[key]; !exists {
+			index.byKey[key] = position
+		}
+
+		key.Id = 0
+		key.OriginalLabel = decodeAscii(reading.OriginalLabelAscii[:], codepage)
+		if _, exists := index.byLabel[key]; !exists {
+			index.byLabel[key] = position
+		}
+	}
+
+	return index
+}
+
+// find returns the position of the reading with the key or -1 when there is none.
+func (index *readingIndex) find(key ReadingKey) int {
+	label := key.OriginalLabel
+	key.OriginalLabel = ""
+	if position, ok := index.byKey[key]; ok {
+		return position
+	}
+
+	if label == "" {
+		return -1
+	}
+
+	if position, ok := index.byLabel[ReadingKey{SensorId: key.SensorId, SensorInstance: key.SensorInstance, OriginalLabel: label}]; ok {
+		return position
+	}
+
+	return -1
+}
+
+// findCombos returns the positions of the readings with the combinations.
+// Reports false when a reading at a position no longer has its combination, in which case the
+// index is outdated and the positions incomplete. A combination without positions does not exist
+// in the layout of the index.
+func (index *readingIndex) findCombos(pointer uintptr, combos []ReadingIdSensorCombo) ([]int, bool) {
+	positions := make([]int, 0, len(combos))
+	complete := true
+
+	for _, combo := range combos {
+		for _, position := range index.byCombo[combo] {
+			if !index.matchesCombo(pointer, position, combo) {
+				complete = false
+				continue
+			}
+			positions = append(positions, position)
+		}
+	}
+
+	return positions, complete
+}
+
+// matchesCombo reports whether the reading at the position still has the combination.
+func (index *readingIndex) matchesCombo(pointer uintptr, position int, combo ReadingIdSensorCombo) bool {
+	reading := index.layout.readingAt(pointer, position)
+	return reading.Id == combo.Id && reading.SensorIndex == combo.SensorIndex
+}
+
+// matchesKey reports whether the reading at the position still belongs to the sensor of the key.
+// A reading found using the original label is not required to have the Id of the key.
+func (index *readingIndex) matchesKey(pointer uintptr, position int, key ReadingKey) bool {
+	reading := index.layout.readingAt(pointer, position)
+	if reading.SensorIndex >= index.layout.sensorAmount || (reading.Id != key.Id && key.OriginalLabel == "") {
+		return false
+	}
+
+	sensor := index.layout.sensorAt(pointer, reading.SensorIndex)
+	return sensor.SensorId == key.SensorId && sensor.SensorInstance == key.SensorInstance
+}
+
+// getIndex returns the index of the readings, building it when the layout changed since it was
+// last built or when the current index is the given outdated index.
+// The pointer must be validated for the header.
+func (reader *Reader) getIndex(pointer uintptr, info *HwinfoHeader, outdated *readingIndex) *readingIndex {
+	reader.indexMutex.Lock()
+	defer reader.indexMutex.Unlock()
+
+	layout := newSectionLayout(info)
+	codepage := reader.GetCodepage()
+	if reader.index == nil || reader.index == outdated || reader.index.layout != layout || reader.index.codepage != codepage {
+		reader.index = newReadingIndex(pointer, layout, codepage)
+	}
+
+	return reader.index
+}
+
+// ReadingLookup repeatedly finds the readings of a fixed set of keys.
+// The positions of the readings are determined once and reused until the header describes a
+// different layout or a reading at a position no longer matches its key, e.g. because HWiNFO
+// added a sensor.
+// Looking up the readings does not allocate.
+//
+// ReadingLookup has an initializer function, [Reader.NewReadingLookup].
+type ReadingLookup struct {
+	reader    *Reader
+	keys      []ReadingKey
+	index     *readingIndex
+	positions []int
+	readings  []*HwinfoReading
+}
+
+// NewReadingLookup returns a ReadingLookup for the given keys.
+func (reader *Reader) NewReadingLookup(keys []ReadingKey) *ReadingLookup {
+	return &ReadingLookup{
+		reader:    reader,
+		keys:      keys,
+		positions: make([]int, len(keys)),
+		readings:  make([]*HwinfoReading, len(keys)),
+	}
+}
+
+// GetReadings returns the readings of the keys.
+// The reading at index i belongs to keys[i] and is nil when no reading has that key.
+//
+// The returned slice is reused by the next call.
+func (lookup *ReadingLookup) GetReadings(info *HwinfoHeader) ([]*HwinfoReading, error) {
+	pointer, err := lookup.reader.getValidatedPointer(info)
+	if err != nil {
+		return nil, err
+	}
+
+	if !lookup.isValid(pointer, info) {
+		lookup.resolve(pointer, info)
+	}
+
+	for i, position := range lookup.positions {
+		lookup.readings[i] = nil
+		if position >= 0 {
+			lookup.readings[i] = lookup.index.layout.readingAt(pointer, position)
+		}
+	}
+
+	return lookup.readings, nil
+}
+
+// isValid reports whether the resolved positions can be used for the header.
+func (lookup *ReadingLookup) isValid(pointer uintptr, info *HwinfoHeader) bool {
+	if lookup.index == nil || lookup.index.layout != newSectionLayout(info) {
+		return false
+	}
+
+	for i, position := range lookup.positions {
+		if position >= 0 && !lookup.index.matchesKey(pointer, position, lookup.keys[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// resolve determines the positions of the keys, rebuilding the index when it is outdated.
+func (lookup *ReadingLookup) resolve(pointer uintptr, info *HwinfoHeader) {
+	lookup.index = lookup.reader.getIndex(pointer, info, lookup.index)
+
+	for i, key := range lookup.keys {
+		lookup.positions[i] = lookup.index.find(key)
+	}
+}
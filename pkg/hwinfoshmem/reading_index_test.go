@@ -0,0 +1,179 @@
+package hwinfoshmem_test
+
+import (
+	"encoding/binary"
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/hwinfoshmem"
+	"testing"
+)
+
+// swapReadings swaps the readings at the given positions.
+func swapReadings(data []byte, info *hwinfoshmem.HwinfoHeader, i int, j int) {
+	offset, size := int(info.ReadingSectionOffset), int(info.ReadingSize)
+
+	first := data[offset+i*size : offset+(i+1)*size]
+	second := data[offset+j*size : offset+(j+1)*size]
+	temporary := make([]byte, size)
+	copy(temporary, first)
+	copy(first, second)
+	copy(second, temporary)
+}
+
+func TestReadingLookup(t *testing.T) {
+	dataCopy := make([]byte, len(data))
+	copy(dataCopy, data)
+	bytesReader := hwinfoshmem.NewBytesReader(dataCopy)
+
+	info, err := bytesReader.GetHeader()
+	if err != nil {
+		t.Fatalf("failed to get header: %v", err)
+	}
+
+	lookup := bytesReader.NewReadingLookup([]hwinfoshmem.ReadingKey{
+		{SensorId: 0xF0008689, Id: 0x1000005},
+		{SensorId: 0xE0001800, Id: 0x100000A},
+		{SensorId: 0xE0001800, Id: 0xFFFF},
+	})
+
+	readings, err := lookup.GetReadings(info)
+	if err != nil {
+		t.Fatalf("failed to get readings: %v", err)
+	}
+
+	if readings[0].UserLabel.String() != "Water (EC_TEMP1)" || readings[2] != nil {
+		t.Fatalf("unexpected readings %v", readings)
+	}
+
+	allocations := testing.AllocsPerRun(100, func() {
+		_, _ = lookup.GetReadings(info)
+	})
+	if allocations != 0 {
+		t.Errorf("expected no allocations, got %f", allocations)
+	}
+
+	// HWiNFO reorders the readings without changing the layout.
+	swapReadings(dataCopy, info, 4, 6)
+
+	readings, err = lookup.GetReadings(info)
+	if err != nil {
+		t.Fatalf("failed to get readings: %v", err)
+	}
+
+	if readings[0].UserLabel.String() != "Water (EC_TEMP1)" {
+		t.Errorf("expected the moved reading, got %q", readings[0].UserLabel.String())
+	}
+
+	if readings[1].UserLabel.String() != "GPU Hot Spot Temperature" {
+		t.Errorf("expected the moved reading, got %q", readings[1].UserLabel.String())
+	}
+}
+
+func TestGetReadingsByIdReordered(t *testing.T) {
+	dataCopy := make([]byte, len(data))
+	copy(dataCopy, data)
+	bytesReader := hwinfoshmem.NewBytesReader(dataCopy)
+
+	info, err := bytesReader.GetHeader()
+	if err != nil {
+		t.Fatalf("failed to get header: %v", err)
+	}
+
+	ids := []hwinfoshmem.ReadingIdSensorCombo{
+		{Id: 0x100000A, SensorIndex: 23},
+		{Id: 0x1000005, SensorIndex: 6},
+	}
+
+	labels := func() []string {
+		readings, err := bytesReader.GetReadingsById(info, ids)
+		if err != nil {
+			t.Fatalf("failed to get readings: %v", err)
+		}
+
+		result := make([]string, len(readings))
+		for i, reading := range readings {
+			result[i] = reading.UserLabel.String()
+		}
+
+		return result
+	}
+
+	// The readings are returned in the order reported by HWiNFO.
+	if result := labels(); len(result) != 2 || result[0] != "Water (EC_TEMP1)" || result[1] != "GPU Hot Spot Temperature" {
+		t.Fatalf("unexpected readings %q", result)
+	}
+
+	swapReadings(dataCopy, info, 4, 6)
+
+	if result := labels(); len(result) != 2 || result[0] != "GPU Hot Spot Temperature" || result[1] != "Water (EC_TEMP1)" {
+		t.Errorf("unexpected readings after reordering %q", result)
+	}
+}
+
+func TestGetReadingsByIdChanged(t *testing.T) {
+	dataCopy := make([]byte, len(data))
+	copy(dataCopy, data)
+	bytesReader := hwinfoshmem.NewBytesReader(dataCopy)
+
+	info, err := bytesReader.GetHeader()
+	if err != nil {
+		t.Fatalf("failed to get header: %v", err)
+	}
+
+	ids := []hwinfoshmem.ReadingIdSensorCombo{
+		{Id: 0x1000005, SensorIndex: 6},
+		{Id: 0x1234, SensorIndex: 6},
+	}
+
+	readings, err := bytesReader.GetReadingsById(info, ids)
+	if err != nil {
+		t.Fatalf("failed to get readings: %v", err)
+	}
+
+	if len(readings) != 1 {
+		t.Fatalf("expected 1 reading, got %d", len(readings))
+	}
+
+	// HWiNFO changes the Id of the reading at position 4 without changing the layout.
+	offset := int(info.ReadingSectionOffset) + 4*int(info.ReadingSize) + 8
+	binary.LittleEndian.PutUint32(dataCopy[offset:], 0x1234)
+
+	readings, err = bytesReader.GetReadingsById(info, ids)
+	if err != nil {
+		t.Fatalf("failed to get readings: %v", err)
+	}
+
+	if len(readings) != 1 || readings[0].Id != 0x1234 {
+		t.Errorf("expected the reading with the new Id, got %v", readings)
+	}
+}
+
+func TestGetReadingsByIdMissing(t *testing.T) {
+	bytesReader := hwinfoshmem.NewBytesReader(data)
+
+	info, err := bytesReader.GetHeader()
+	if err != nil {
+		t.Fatalf("failed to get header: %v", err)
+	}
+
+	present := []hwinfoshmem.ReadingIdSensorCombo{{Id: 0x1000005, SensorIndex: 6}}
+	missing := []hwinfoshmem.ReadingIdSensorCombo{{Id: 0x1234, SensorIndex: 6}}
+
+	readings, err := bytesReader.GetReadingsById(info, missing)
+	if err != nil {
+		t.Fatalf("failed to get readings: %v", err)
+	}
+
+	if len(readings) != 0 {
+		t.Fatalf("expected no readings, got %d", len(readings))
+	}
+
+	// A reading that does not exist does not cause the index to be built again.
+	presentAllocations := testing.AllocsPerRun(100, func() {
+		_, _ = bytesReader.GetReadingsById(info, present)
+	})
+	missingAllocations := testing.AllocsPerRun(100, func() {
+		_, _ = bytesReader.GetReadingsById(info, missing)
+	})
+	if missingAllocations > presentAllocations {
+		t.Errorf("expected at most %f allocations, got %f", presentAllocations, missingAllocations)
+	}
+}
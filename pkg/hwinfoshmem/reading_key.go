@@ -0,0 +1,127 @@
+package hwinfoshmem
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidReadingKey is returned when parsing a string that is not a valid [ReadingKey].
+var ErrInvalidReadingKey = errors.New("invalid reading key")
+
+// ReadingKey identifies a reading independently of the position of its sensor.
+// Unlike [ReadingIdSensorCombo], it stays valid when HWiNFO adds, removes, or reorders sensors,
+// which makes it suitable for storing in configuration files.
+//
+// The string form, see [ReadingKey.String], can be used in configuration files and metric labels.
+type ReadingKey struct {
+	// SensorId of the sensor the reading belongs to.
+	SensorId uint32
+
+	// SensorInstance of the sensor the reading belongs to.
+	SensorInstance uint32
+
+	// Id of the reading within the sensor.
+	Id uint32
+
+	// Optional. When set and no reading of the sensor has the Id, the reading of the sensor with
+	// this original label is used instead.
+	OriginalLabel string
+}
+
+// Key returns the key of the reading, without an original label.
+// When the reading has no sensor, the SensorId and SensorInstance are zero.
+func (reading *Reading) Key() ReadingKey {
+	key := ReadingKey{Id: reading.Id}
+
+	if reading.Sensor != nil {
+		key.SensorId = reading.Sensor.SensorId
+		key.SensorInstance = reading.Sensor.SensorInstance
+	}
+
+	return key
+}
+
+// ParseReadingKey parses the string form of a key, see [ReadingKey.String].
+func ParseReadingKey(s string) (ReadingKey, error) {
+	parts := strings.SplitN(s, "/", 4)
+	if len(parts) < 3 {
+		return ReadingKey{}, fmt.Errorf("%w %q: expected sensor id, instance, and reading id", ErrInvalidReadingKey, s)
+	}
+
+	var key ReadingKey
+	fields := []*uint32{&key.SensorId, &key.SensorInstance, &key.Id}
+	for i, field := range fields {
+		value, err := strconv.ParseUint(parts[i], 0, 32)
+		if err != nil {
+			return ReadingKey{}, fmt.Errorf("%w %q: %w", ErrInvalidReadingKey, s, err)
+		}
+		*field = uint32(value)
+	}
+
+	if len(parts) == 4 {
+		key.OriginalLabel = parts[3]
+	}
+
+	return key, nil
+}
+
+// String returns the key in the form sensorId/sensorInstance/id with the ids in hexadecimal,
+// followed by /originalLabel when the original label is set.
+// E.g. 0xf0000300/0/0x1000000 or 0xf0000300/0/0x1000000/CPU (Tctl/Tdie).
+func (key ReadingKey) String() string {
+	s := fmt.Sprintf("%#x/%d/%#x", key.SensorId, key.SensorInstance, key.Id)
+
+	if key.OriginalLabel != "" {
+		s += "/" + key.OriginalLabel
+	}
+
+	return s
+}
+
+// MarshalText implements [encoding.TextMarshaler] using [ReadingKey.String].
+func (key ReadingKey) MarshalText() ([]byte, error) {
+	return []byte(key.String()), nil
+}
+
+// UnmarshalText implements [encoding.TextUnmarshaler] using [ParseReadingKey].
+func (key *ReadingKey) UnmarshalText(text []byte) error {
+	parsed, err := ParseReadingKey(string(text))
+	if err != nil {
+		return err
+	}
+
+	*key = parsed
+	return nil
+}
+
+// GetReadingByKey returns the reading with the given key or nil when it does not exist.
+func (snapshot *Snapshot) GetReadingByKey(key ReadingKey) *Reading {
+	var fallback *Reading
+
+	for _, sensor := range snapshot.Sensors {
+		if sensor.SensorId != key.SensorId || sensor.SensorInstance != key.SensorInstance {
+			continue
+		}
+
+		for _, reading := range sensor.Readings {
+			if reading.Id == key.Id {
+				return reading
+			}
+
+			if fallback == nil && key.OriginalLabel != "" && reading.OriginalLabel == key.OriginalLabel {
+				fallback = reading
+			}
+		}
+	}
+
+	return fallback
+}
+
+// GetReadingsByKey returns the readings with the given keys.
+// The reading at index i belongs to keys[i] and is nil when no reading has that key.
+// See [ReadingLookup] for repeated lookups of the same keys.
+func (reader *Reader) GetReadingsByKey(info *HwinfoHeader, keys []ReadingKey) ([]*HwinfoReading, error) {
+	return reader.NewReadingLookup(keys).GetReadings(info)
+}
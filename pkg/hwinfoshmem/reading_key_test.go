@@ -0,0 +1,126 @@
+package hwinfoshmem_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/hwinfoshmem"
+	"testing"
+)
+
+func ExampleParseReadingKey() {
+	// E.g. read from a configuration file.
+	key, err := hwinfoshmem.ParseReadingKey("0xf0008689/0/0x1000005")
+	if err != nil {
+		fmt.Printf("Failed to parse key: %s\n", err)
+		return
+	}
+
+	var bytesReader = hwinfoshmem.NewBytesReader(data)
+
+	hwInfo, err := bytesReader.GetHeader()
+	if err != nil {
+		fmt.Printf("Failed to get header: %s\n", err)
+		return
+	}
+
+	snapshot, err := hwinfoshmem.NewSnapshot(bytesReader, hwInfo)
+	if err != nil {
+		fmt.Printf("Failed to create snapshot: %s\n", err)
+		return
+	}
+
+	reading := snapshot.GetReadingByKey(key)
+	fmt.Printf("%s: %.2f %s\n", reading.UserLabel, reading.Value, reading.Unit)
+
+	// Output:
+	// Water (EC_TEMP1): 27.00 °C
+}
+
+func TestReadingKeyText(t *testing.T) {
+	keys := []hwinfoshmem.ReadingKey{
+		{SensorId: 0xF0000300, SensorInstance: 1, Id: 0x1000000},
+		{SensorId: 0xF0000300, Id: 7, OriginalLabel: "CPU (Tctl/Tdie)"},
+	}
+
+	for _, key := range keys {
+		parsed, err := hwinfoshmem.ParseReadingKey(key.String())
+		if err != nil {
+			t.Fatalf("failed to parse %q: %v", key.String(), err)
+		}
+
+		if parsed != key {
+			t.Errorf("expected %+v, got %+v", key, parsed)
+		}
+	}
+
+	encoded, err := json.Marshal(map[string]hwinfoshmem.ReadingKey{"cpu": keys[1]})
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+
+	if string(encoded) != `{"cpu":"0xf0000300/0/0x7/CPU (Tctl/Tdie)"}` {
+		t.Errorf("unexpected JSON %s", encoded)
+	}
+
+	var decoded map[string]hwinfoshmem.ReadingKey
+	if err = json.Unmarshal(encoded, &decoded); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+
+	if decoded["cpu"] != keys[1] {
+		t.Errorf("expected %+v, got %+v", keys[1], decoded["cpu"])
+	}
+
+	for _, invalid := range []string{"", "0xf0000300/0", "0xf0000300/x/1", "0x1ffffffff/0/1"} {
+		if _, err = hwinfoshmem.ParseReadingKey(invalid); !errors.Is(err, hwinfoshmem.ErrInvalidReadingKey) {
+			t.Errorf("expected ErrInvalidReadingKey for %q, got %v", invalid, err)
+		}
+	}
+}
+
+func TestGetReadingsByKey(t *testing.T) {
+	snapshot := newTestSnapshot(t)
+	water := snapshot.Readings[4]
+
+	keys := []hwinfoshmem.ReadingKey{
+		water.Key(),
+		{SensorId: water.Sensor.SensorId, Id: 0xFFFF, OriginalLabel: "EC_TEMP1"},
+		{SensorId: water.Sensor.SensorId, Id: 0xFFFF},
+		{SensorId: water.Sensor.SensorId, SensorInstance: 1, Id: water.Id},
+	}
+	expected := []string{"Water (EC_TEMP1)", "Water (EC_TEMP1)", "", ""}
+
+	for i, key := range keys {
+		label := ""
+		if reading := snapshot.GetReadingByKey(key); reading != nil {
+			label = reading.UserLabel
+		}
+
+		if label != expected[i] {
+			t.Errorf("snapshot: expected %q for %s, got %q", expected[i], key, label)
+		}
+	}
+
+	bytesReader := hwinfoshmem.NewBytesReader(data)
+	info, err := bytesReader.GetHeader()
+	if err != nil {
+		t.Fatalf("failed to get header: %v", err)
+	}
+
+	readings, err := bytesReader.GetReadingsByKey(info, keys)
+	if err != nil {
+		t.Fatalf("failed to get readings: %v", err)
+	}
+
+	for i, reading := range readings {
+		label := ""
+		if reading != nil {
+			label = reading.UserLabel.String()
+		}
+
+		if label != expected[i] {
+			t.Errorf("reader: expected %q for %s, got %q", expected[i], keys[i], label)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package hwinfoshmem
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrUnknownReadingType is returned when parsing a string that is not a [ReadingType].
+var ErrUnknownReadingType = errors.New("unknown reading type")
+
+// readingTypeInfo contains the metadata of a known reading type.
+type readingTypeInfo struct {
+	name          string
+	canonicalUnit string
+	metricSuffix  string
+}
+
+var readingTypeInfos = map[ReadingType]readingTypeInfo{
+	SENSOR_TYPE_NONE:    {name: "none"},
+	SENSOR_TYPE_TEMP:    {name: "temp", canonicalUnit: "°C", metricSuffix: "celsius"},
+	SENSOR_TYPE_VOLT:    {name: "volt", canonicalUnit: "V", metricSuffix: "volts"},
+	SENSOR_TYPE_FAN:     {name: "fan", canonicalUnit: "RPM", metricSuffix: "rpm"},
+	SENSOR_TYPE_CURRENT: {name: "current", canonicalUnit: "A", metricSuffix: "amperes"},
+	SENSOR_TYPE_POWER:   {name: "power", canonicalUnit: "W", metricSuffix: "watts"},
+	SENSOR_TYPE_CLOCK:   {name: "clock", canonicalUnit: "Hz", metricSuffix: "hertz"},
+	SENSOR_TYPE_USAGE:   {name: "usage", canonicalUnit: "%", metricSuffix: "percent"},
+	SENSOR_TYPE_OTHER:   {name: "other"},
+}
+
+// String returns the name of the type, e.g. temp, or type(N) for types unknown to this library.
+func (readingType ReadingType) String() string {
+	if info, ok := readingTypeInfos[readingType]; ok {
+		return info.name
+	}
+
+	return fmt.Sprintf("type(%d)", uint32(readingType))
+}
+
+// IsKnown reports whether the type is one of the SENSOR_TYPE constants.
+func (readingType ReadingType) IsKnown() bool {
+	_, ok := readingTypeInfos[readingType]
+	return ok
+}
+
+// CanonicalUnit returns the unit that values of this type are expressed in after normalization,
+// e.g. °C for temperatures and Hz for clocks.
+// HWiNFO can report values in other units, e.g. °F or MHz, which need to be converted to the
+// canonical unit.
+// Returns an empty string for types without a unit such as other, none, and unknown types.
+func (readingType ReadingType) CanonicalUnit() string {
+	return readingTypeInfos[readingType].canonicalUnit
+}
+
+// MetricSuffix returns the suffix for metric names of this type, matching the canonical unit,
+// e.g. celsius for temperatures and hertz for clocks.
+// Returns an empty string for types without a unit such as other, none, and unknown types.
+func (readingType ReadingType) MetricSuffix() string {
+	return readingTypeInfos[readingType].metricSuffix
+}
+
+// ParseReadingType parses the name of a type as returned by [ReadingType.String], ignoring case.
+// Numbers, in decimal or hexadecimal, are accepted as well, also when written as type(N).
+func ParseReadingType(s string) (ReadingType, error) {
+	name := strings.ToLower(strings.TrimSpace(s))
+
+	for readingType, info := range readingTypeInfos {
+		if info.name == name {
+			return readingType, nil
+		}
+	}
+
+	if strings.HasPrefix(name, "type(") && strings.HasSuffix(name, ")") {
+		name = name[len("type(") : len(name)-1]
+	}
+
+	number, err := strconv.ParseUint(name, 0, 32)
+	if err != nil {
+		return 0, fmt.Errorf("%w %q", ErrUnknownReadingType, s)
+	}
+
+	return ReadingType(number), nil
+}
+
+// MarshalText implements [encoding.TextMarshaler] using [ReadingType.String].
+func (readingType ReadingType) MarshalText() ([]byte, error) {
+	return []byte(readingType.String()), nil
+}
+
+// UnmarshalText implements [encoding.TextUnmarshaler] using [ParseReadingType].
+func (readingType *ReadingType) UnmarshalText(text []byte) error {
+	parsed, err := ParseReadingType(string(text))
+	if err != nil {
+		return err
+	}
+
+	*readingType = parsed
+	return nil
+}
+
+// UnmarshalJSON implements [json.Unmarshaler], accepting both the name and the number of a type.
+func (readingType *ReadingType) UnmarshalJSON(data []byte) error {
+	var number uint32
+	if err := json.Unmarshal(data, &number); err == nil {
+		*readingType = ReadingType(number)
+		return nil
+	}
+
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return fmt.Errorf("%w %s", ErrUnknownReadingType, data)
+	}
+
+	return readingType.UnmarshalText([]byte(text))
+}
@@ -0,0 +1,69 @@
+package hwinfoshmem_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/hwinfoshmem"
+	"testing"
+)
+
+func ExampleReadingType() {
+	for _, readingType := range []hwinfoshmem.ReadingType{hwinfoshmem.SENSOR_TYPE_TEMP, hwinfoshmem.SENSOR_TYPE_CLOCK, 42} {
+		fmt.Printf("%s %q %q\n", readingType, readingType.CanonicalUnit(), readingType.MetricSuffix())
+	}
+
+	// Output:
+	// temp "°C" "celsius"
+	// clock "Hz" "hertz"
+	// type(42) "" ""
+}
+
+func TestParseReadingType(t *testing.T) {
+	for readingType := hwinfoshmem.SENSOR_TYPE_NONE; readingType <= hwinfoshmem.SENSOR_TYPE_OTHER+1; readingType++ {
+		parsed, err := hwinfoshmem.ParseReadingType(readingType.String())
+		if err != nil {
+			t.Fatalf("failed to parse %s: %v", readingType, err)
+		}
+
+		if parsed != readingType {
+			t.Errorf("expected %d, got %d", readingType, parsed)
+		}
+	}
+
+	for input, expected := range map[string]hwinfoshmem.ReadingType{"TEMP": 1, " fan ": 3, "7": 7, "0x10": 16} {
+		if parsed, err := hwinfoshmem.ParseReadingType(input); err != nil || parsed != expected {
+			t.Errorf("expected %d for %q, got %d, %v", expected, input, parsed, err)
+		}
+	}
+
+	for _, input := range []string{"", "warm", "type(x)", "-1"} {
+		if _, err := hwinfoshmem.ParseReadingType(input); !errors.Is(err, hwinfoshmem.ErrUnknownReadingType) {
+			t.Errorf("expected ErrUnknownReadingType for %q, got %v", input, err)
+		}
+	}
+}
+
+func TestReadingTypeJSON(t *testing.T) {
+	encoded, err := json.Marshal([]hwinfoshmem.ReadingType{hwinfoshmem.SENSOR_TYPE_POWER, 12})
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+
+	if string(encoded) != `["power","type(12)"]` {
+		t.Errorf("unexpected JSON %s", encoded)
+	}
+
+	var decoded []hwinfoshmem.ReadingType
+	if err = json.Unmarshal([]byte(`["power","type(12)",3]`), &decoded); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+
+	if fmt.Sprint(decoded) != "[power type(12) fan]" {
+		t.Errorf("unexpected types %v", decoded)
+	}
+
+	if err = json.Unmarshal([]byte(`[true]`), &decoded); !errors.Is(err, hwinfoshmem.ErrUnknownReadingType) {
+		t.Errorf("expected ErrUnknownReadingType, got %v", err)
+	}
+}
@@ -15,6 +15,7 @@ type HwinfoSensor struct {
 	SensorNameAscii HwinfoSensorStringAscii
 
 	// Display name of the sensor. Might be renamed by the user.
+	// Added in version 2 of the layout, see [LayoutInfo].
 	// E.g.
 	//   - GIGABYTE B650E AORUS MASTER (ITE IT8689E)
 	//   - CPU [#0]: AMD Ryzen 9 7950X
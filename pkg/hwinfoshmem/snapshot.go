@@ -0,0 +1,189 @@
+package hwinfoshmem
+
+import (
+	"fmt"
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/util/bytesutil"
+	"time"
+)
+
+// Snapshot contains HWiNFO's data at a single point in time, converted to plain Go values.
+// Unlike the results of [Reader], a Snapshot does not reference the shared memory and can be kept
+// and used after the lock has been released.
+//
+// Snapshot has an initializer function, [NewSnapshot].
+type Snapshot struct {
+	// Whether HWiNFO was updating the shared memory, see [HwinfoHeader.IsActive].
+	Active bool
+
+	// "HWiS" when HWiNFO was active, "DAED" (sic.) when it was not.
+	Status string
+
+	// Structure layout version, see [HwinfoHeader].
+	Version uint32
+
+	// Structure layout revision, see [HwinfoHeader].
+	Revision uint32
+
+	// The time when the last update to the data occurred.
+	LastUpdate time.Time
+
+	// Time between updates of the data by HWiNFO. Zero when the layout does not contain it.
+	PollingPeriod time.Duration
+
+	// The layout of the data the snapshot was created from.
+	Layout LayoutInfo
+
+	// The sensors in the order reported by HWiNFO.
+	Sensors []*Sensor
+
+	// All readings in the order reported by HWiNFO.
+	Readings []*Reading
+}
+
+// Sensor groups readings, see [HwinfoSensor].
+type Sensor struct {
+	// The position of the sensor in the sensor section. Readings refer to their sensor using this
+	// index.
+	Index uint32
+
+	// A unique Sensor ID
+	SensorId uint32
+
+	// The instance of the sensor (together with SensorId forms a unique ID)
+	SensorInstance uint32
+
+	// Original name of sensor in English.
+	SensorNameOriginal string
+
+	// Display name of the sensor. Might be renamed by the user.
+	SensorName string
+
+	// The readings that belong to this sensor.
+	Readings []*Reading
+}
+
+// Reading is a single value reported by HWiNFO, see [HwinfoReading].
+type Reading struct {
+	// The sensor this reading belongs to. Nil when HWiNFO reported a sensor index that does not
+	// exist.
+	Sensor *Sensor
+
+	// The type of reading.
+	Type ReadingType
+
+	// Index of the Sensor this reading belongs to.
+	SensorIndex uint32
+
+	// A unique ID of the reading within a particular sensor.
+	Id uint32
+
+	// Original Label in English language.
+	OriginalLabel string
+
+	// Displayed label which might have been renamed by the user.
+	UserLabel string
+
+	// The unit of the reading. E.g. °C, RPM.
+	Unit string
+
+	// The value of the reading.
+	Value float64
+
+	// The minimum value of the reading.
+	ValueMin float64
+
+	// The maximum value of the reading.
+	ValueMax float64
+
+	// The average value of the reading.
+	ValueAvg float64
+}
+
+// NewSnapshot copies the sensors and readings described by the header from the source.
+// When the source is a [MemoryReader], the lock must be held while calling this function but can
+// be released afterward.
+//
+// The ASCII strings are converted using the codepage of the source.
+// When the layout does not contain the UTF-8 strings, the ASCII fields are used instead.
+func NewSnapshot(source Source, info *HwinfoHeader) (*Snapshot, error) {
+	layout, err := DetectLayout(info)
+	if err != nil {
+		return nil, err
+	}
+
+	codepage := source.GetCodepage()
+	if !codepage.IsSupported() {
+		return nil, fmt.Errorf("error creating snapshot: %w: %s", bytesutil.ErrUnsupportedCodepage, codepage)
+	}
+
+	hwinfoSensors, err := source.GetSensors(info)
+	if err != nil {
+		return nil, err
+	}
+
+	hwinfoReadings, err := source.GetReadings(info)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &Snapshot{
+		Active:     info.IsActive(),
+		Status:     info.GetStatus(),
+		Version:    info.Version,
+		Revision:   info.Revision,
+		LastUpdate: info.GetLastUpdateTime(),
+		Layout:     layout,
+		Sensors:    make([]*Sensor, len(hwinfoSensors)),
+		Readings:   make([]*Reading, len(hwinfoReadings)),
+	}
+
+	if layout.HasPollingPeriod {
+		snapshot.PollingPeriod = time.Duration(info.PollingPeriodInMs) * time.Millisecond
+	}
+
+	for i, hwinfoSensor := range hwinfoSensors {
+		sensor := &Sensor{
+			Index:              uint32(i),
+			SensorId:           hwinfoSensor.SensorId,
+			SensorInstance:     hwinfoSensor.SensorInstance,
+			SensorNameOriginal: decodeAscii(hwinfoSensor.SensorNameOriginalAscii[:], codepage),
+			SensorName:         hwinfoSensor.SensorName.String(),
+			Readings:           make([]*Reading, 0),
+		}
+
+		if !layout.HasUtf8Strings {
+			sensor.SensorName = decodeAscii(hwinfoSensor.SensorNameAscii[:], codepage)
+		}
+
+		snapshot.Sensors[i] = sensor
+	}
+
+	for i, hwinfoReading := range hwinfoReadings {
+		reading := &Reading{
+			Type:          hwinfoReading.Type,
+			SensorIndex:   hwinfoReading.SensorIndex,
+			Id:            hwinfoReading.Id,
+			OriginalLabel: decodeAscii(hwinfoReading.OriginalLabelAscii[:], codepage),
+			UserLabel:     hwinfoReading.UserLabel.String(),
+			Unit:          hwinfoReading.Unit.String(),
+			Value:         hwinfoReading.Value.ToFloat64(),
+			ValueMin:      hwinfoReading.ValueMin.ToFloat64(),
+			ValueMax:      hwinfoReading.ValueMax.ToFloat64(),
+			ValueAvg:      hwinfoReading.ValueAvg.ToFloat64(),
+		}
+
+		if !layout.HasUtf8Strings {
+			reading.UserLabel = decodeAscii(hwinfoReading.UserLabelAscii[:], codepage)
+			reading.Unit = decodeAscii(hwinfoReading.UnitAscii[:], codepage)
+		}
+
+		if int(reading.SensorIndex) < len(snapshot.Sensors) {
+			reading.Sensor = snapshot.Sensors[reading.SensorIndex]
+			reading.Sensor.Readings = append(reading.Sensor.Readings, reading)
+		}
+
+		snapshot.Readings[i] = reading
+	}
+
+	return snapshot, nil
+}
@@ -0,0 +1,200 @@
+package hwinfoshmem
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"math"
+	"os"
+	"time"
+)
+
+// ErrInvalidSnapshotFile is returned when reading a snapshot file that is corrupt or of an
+// unsupported format version.
+var ErrInvalidSnapshotFile = errors.New("invalid snapshot file")
+
+// SnapshotFileVersion is the format version written by [SnapshotFile.MarshalBinary].
+const SnapshotFileVersion = 1
+
+// snapshotFileMagic starts every snapshot file. It differs from the status of the shared memory
+// so that snapshot files and raw copies can be told apart.
+var snapshotFileMagic = [8]byte{'H', 'W', 'i', 'S', 'N', 'A', 'P', 0x1a}
+
+// Offsets of the fields in the header of a snapshot file, all little endian. The hostname follows
+// the header and is padded with nul bytes so that the data starts at a multiple of 8 bytes.
+const (
+	snapshotFileOffsetFormatVersion = 8  // uint16
+	snapshotFileOffsetDataOffset    = 12 // uint32
+	snapshotFileOffsetCaptureTime   = 16 // int64, Unix time in nanoseconds, 0 when unknown
+	snapshotFileOffsetVersion       = 24 // uint32
+	snapshotFileOffsetRevision      = 28 // uint32
+	snapshotFileOffsetDataSize      = 32 // uint64
+	snapshotFileOffsetChecksum      = 40 // uint32, CRC-32 of the file excluding this field
+	snapshotFileOffsetHostnameSize  = 44 // uint16
+	snapshotFileHeaderSize          = 46
+)
+
+// SnapshotFile is a copy of HWiNFO's shared memory together with information about where and when
+// it was captured.
+// Use [SnapshotFile.WriteTo] to store it and [ReadSnapshotFile] or [NewBytesReader] to read it.
+//
+// SnapshotFile has an initializer function, [NewSnapshotFile].
+type SnapshotFile struct {
+	// The format version the file was read with, see [SnapshotFileVersion].
+	FormatVersion uint16
+
+	// The time the copy was made. The zero time when unknown.
+	CaptureTime time.Time
+
+	// The name of the machine the copy was made on.
+	Hostname string
+
+	// The version of the shared memory layout, see [HwinfoHeader].
+	Version uint32
+
+	// The revision of the shared memory layout, see [HwinfoHeader].
+	Revision uint32
+
+	// The copy of the shared memory, e.g. [BytesReader.Bytes] as returned by [MemoryReader.Copy].
+	Data []byte
+}
+
+// NewSnapshotFile wraps a copy of the shared memory captured now on this machine.
+// The version and revision are taken from the header in the data when present.
+func NewSnapshotFile(data []byte) *SnapshotFile {
+	file := &SnapshotFile{
+		FormatVersion: SnapshotFileVersion,
+		CaptureTime:   time.Now(),
+		Data:          data,
+	}
+
+	if hostname, err := os.Hostname(); err == nil {
+		file.Hostname = hostname
+	}
+
+	if info, err := NewDecoder(data).GetHeader(); err == nil {
+		file.Version = info.Version
+		file.Revision = info.Revision
+	}
+
+	return file
+}
+
+// IsSnapshotFile reports whether the bytes start like a snapshot file rather than like a raw copy
+// of the shared memory.
+func IsSnapshotFile(data []byte) bool {
+	return bytes.HasPrefix(data, snapshotFileMagic[:])
+}
+
+// ReadSnapshotFile reads a snapshot file written by [SnapshotFile.WriteTo].
+func ReadSnapshotFile(reader io.Reader) (*SnapshotFile, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("error reading snapshot file: %w", err)
+	}
+
+	file := &SnapshotFile{}
+	if err = file.UnmarshalBinary(data); err != nil {
+		return nil, err
+	}
+
+	return file, nil
+}
+
+// MarshalBinary implements [encoding.BinaryMarshaler] by encoding the file in format version
+// [SnapshotFileVersion].
+func (file *SnapshotFile) MarshalBinary() ([]byte, error) {
+	if len(file.Hostname) > math.MaxUint16 {
+		return nil, fmt.Errorf("hostname of %d bytes exceeds the maximum of %d", len(file.Hostname), math.MaxUint16)
+	}
+
+	dataOffset := (snapshotFileHeaderSize + len(file.Hostname) + 7) &^ 7
+	result := make([]byte, dataOffset+len(file.Data))
+
+	var captureTime int64
+	if !file.CaptureTime.IsZero() {
+		captureTime = file.CaptureTime.UnixNano()
+	}
+
+	copy(result, snapshotFileMagic[:])
+	binary.LittleEndian.PutUint16(result[snapshotFileOffsetFormatVersion:], SnapshotFileVersion)
+	binary.LittleEndian.PutUint32(result[snapshotFileOffsetDataOffset:], uint32(dataOffset))
+	binary.LittleEndian.PutUint64(result[snapshotFileOffsetCaptureTime:], uint64(captureTime))
+	binary.LittleEndian.PutUint32(result[snapshotFileOffsetVersion:], file.Version)
+	binary.LittleEndian.PutUint32(result[snapshotFileOffsetRevision:], file.Revision)
+	binary.LittleEndian.PutUint64(result[snapshotFileOffsetDataSize:], uint64(len(file.Data)))
+	binary.LittleEndian.PutUint16(result[snapshotFileOffsetHostnameSize:], uint16(len(file.Hostname)))
+	copy(result[snapshotFileHeaderSize:], file.Hostname)
+	copy(result[dataOffset:], file.Data)
+	binary.LittleEndian.PutUint32(result[snapshotFileOffsetChecksum:], snapshotFileChecksum(result))
+
+	return result, nil
+}
+
+// WriteTo implements [io.WriterTo] by writing the file in format version [SnapshotFileVersion].
+func (file *SnapshotFile) WriteTo(writer io.Writer) (int64, error) {
+	data, err := file.MarshalBinary()
+	if err != nil {
+		return 0, err
+	}
+
+	written, err := writer.Write(data)
+	return int64(written), err
+}
+
+// UnmarshalBinary implements [encoding.BinaryUnmarshaler].
+// The checksum is verified. Data refers to the given bytes, it is not copied.
+func (file *SnapshotFile) UnmarshalBinary(data []byte) error {
+	if !IsSnapshotFile(data) {
+		return fmt.Errorf("%w: missing magic bytes", ErrInvalidSnapshotFile)
+	}
+
+	if len(data) < snapshotFileHeaderSize {
+		return fmt.Errorf("%w: snapshot file header needs %d bytes, got %d", ErrTruncated, snapshotFileHeaderSize, len(data))
+	}
+
+	formatVersion := binary.LittleEndian.Uint16(data[snapshotFileOffsetFormatVersion:])
+	if formatVersion == 0 || formatVersion > SnapshotFileVersion {
+		return fmt.Errorf("%w: unsupported format version %d", ErrInvalidSnapshotFile, formatVersion)
+	}
+
+	dataOffset := uint64(binary.LittleEndian.Uint32(data[snapshotFileOffsetDataOffset:]))
+	dataSize := binary.LittleEndian.Uint64(data[snapshotFileOffsetDataSize:])
+	hostnameEnd := uint64(snapshotFileHeaderSize) + uint64(binary.LittleEndian.Uint16(data[snapshotFileOffsetHostnameSize:]))
+
+	if dataOffset < hostnameEnd {
+		return fmt.Errorf("%w: data offset %d overlaps the header", ErrInvalidSnapshotFile, dataOffset)
+	}
+
+	if dataSize > uint64(len(data)) || dataOffset > uint64(len(data))-dataSize {
+		return fmt.Errorf("%w: snapshot file needs %d bytes, got %d", ErrTruncated, dataOffset+dataSize, len(data))
+	}
+
+	if checksum := binary.LittleEndian.Uint32(data[snapshotFileOffsetChecksum:]); checksum != snapshotFileChecksum(data[:dataOffset+dataSize]) {
+		return fmt.Errorf("%w: checksum mismatch", ErrInvalidSnapshotFile)
+	}
+
+	*file = SnapshotFile{
+		FormatVersion: formatVersion,
+		Hostname:      string(data[snapshotFileHeaderSize:hostnameEnd]),
+		Version:       binary.LittleEndian.Uint32(data[snapshotFileOffsetVersion:]),
+		Revision:      binary.LittleEndian.Uint32(data[snapshotFileOffsetRevision:]),
+		Data:          data[dataOffset : dataOffset+dataSize : dataOffset+dataSize],
+	}
+
+	if captureTime := int64(binary.LittleEndian.Uint64(data[snapshotFileOffsetCaptureTime:])); captureTime != 0 {
+		file.CaptureTime = time.Unix(0, captureTime)
+	}
+
+	return nil
+}
+
+// snapshotFileChecksum returns the CRC-32 of an encoded snapshot file, skipping the checksum
+// field.
+func snapshotFileChecksum(data []byte) uint32 {
+	checksum := crc32.ChecksumIEEE(data[:snapshotFileOffsetChecksum])
+	return crc32.Update(checksum, crc32.IEEETable, data[snapshotFileOffsetChecksum+4:])
+}
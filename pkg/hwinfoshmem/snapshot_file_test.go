@@ -0,0 +1,133 @@
+package hwinfoshmem_test
+
+import (
+	"bytes"
+	"errors"
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/hwinfoshmem"
+	"testing"
+	"time"
+)
+
+func newTestSnapshotFile() *hwinfoshmem.SnapshotFile {
+	file := hwinfoshmem.NewSnapshotFile(data)
+	file.CaptureTime = time.Unix(1694966200, 123)
+	file.Hostname = "workstation"
+
+	return file
+}
+
+func TestSnapshotFile(t *testing.T) {
+	var buffer bytes.Buffer
+	if _, err := newTestSnapshotFile().WriteTo(&buffer); err != nil {
+		t.Fatalf("failed to write snapshot file: %v", err)
+	}
+
+	if !hwinfoshmem.IsSnapshotFile(buffer.Bytes()) || hwinfoshmem.IsSnapshotFile(data) {
+		t.Error("expected only the snapshot file to be detected")
+	}
+
+	file, err := hwinfoshmem.ReadSnapshotFile(&buffer)
+	if err != nil {
+		t.Fatalf("failed to read snapshot file: %v", err)
+	}
+
+	if file.FormatVersion != hwinfoshmem.SnapshotFileVersion {
+		t.Errorf("expected format version %d, got %d", hwinfoshmem.SnapshotFileVersion, file.FormatVersion)
+	}
+
+	if !file.CaptureTime.Equal(time.Unix(1694966200, 123)) {
+		t.Errorf("unexpected capture time %s", file.CaptureTime)
+	}
+
+	if file.Hostname != "workstation" {
+		t.Errorf("expected hostname workstation, got %q", file.Hostname)
+	}
+
+	info, _ := hwinfoshmem.NewDecoder(data).GetHeader()
+	if file.Version != info.Version || file.Revision != info.Revision {
+		t.Errorf("expected version %d.%d, got %d.%d", info.Version, info.Revision, file.Version, file.Revision)
+	}
+
+	if !bytes.Equal(file.Data, data) {
+		t.Error("data differs from the original")
+	}
+}
+
+func TestSnapshotFileZeroCaptureTime(t *testing.T) {
+	encoded, err := (&hwinfoshmem.SnapshotFile{Data: data}).MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to encode snapshot file: %v", err)
+	}
+
+	file := &hwinfoshmem.SnapshotFile{}
+	if err = file.UnmarshalBinary(encoded); err != nil {
+		t.Fatalf("failed to decode snapshot file: %v", err)
+	}
+
+	if !file.CaptureTime.IsZero() || file.Hostname != "" {
+		t.Errorf("expected zero capture time and hostname, got %s and %q", file.CaptureTime, file.Hostname)
+	}
+}
+
+func TestSnapshotFileCorrupt(t *testing.T) {
+	encoded, err := newTestSnapshotFile().MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to encode snapshot file: %v", err)
+	}
+
+	for _, offset := range []int{20, 50, len(encoded) - 1} {
+		corrupt := bytes.Clone(encoded)
+		corrupt[offset] ^= 0xff
+
+		if err = (&hwinfoshmem.SnapshotFile{}).UnmarshalBinary(corrupt); !errors.Is(err, hwinfoshmem.ErrInvalidSnapshotFile) {
+			t.Errorf("expected ErrInvalidSnapshotFile when corrupting offset %d, got %v", offset, err)
+		}
+	}
+
+	unsupported := bytes.Clone(encoded)
+	unsupported[8] = 2
+	if err = (&hwinfoshmem.SnapshotFile{}).UnmarshalBinary(unsupported); !errors.Is(err, hwinfoshmem.ErrInvalidSnapshotFile) {
+		t.Errorf("expected ErrInvalidSnapshotFile for an unsupported version, got %v", err)
+	}
+
+	for _, size := range []int{20, len(encoded) - 1} {
+		if err = (&hwinfoshmem.SnapshotFile{}).UnmarshalBinary(encoded[:size]); !errors.Is(err, hwinfoshmem.ErrTruncated) {
+			t.Errorf("expected ErrTruncated for %d bytes, got %v", size, err)
+		}
+	}
+}
+
+func TestBytesReaderSnapshotFile(t *testing.T) {
+	encoded, err := newTestSnapshotFile().MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to encode snapshot file: %v", err)
+	}
+
+	reader := hwinfoshmem.NewBytesReader(encoded)
+	if reader.SnapshotFile == nil || reader.SnapshotFile.Hostname != "workstation" {
+		t.Fatalf("expected the snapshot file to be detected, got %+v", reader.SnapshotFile)
+	}
+
+	info, err := reader.GetHeader()
+	if err != nil {
+		t.Fatalf("failed to get header: %v", err)
+	}
+
+	readings, err := reader.GetReadings(info)
+	if err != nil {
+		t.Fatalf("failed to get readings: %v", err)
+	}
+
+	if len(readings) != 7 || readings[0].UserLabel.String() != "CPU (Tctl/Tdie)" {
+		t.Errorf("unexpected readings from snapshot file")
+	}
+
+	encoded[len(encoded)-1] ^= 0xff
+	if _, err = hwinfoshmem.NewBytesReader(encoded).GetHeader(); !errors.Is(err, hwinfoshmem.ErrInvalidSnapshotFile) {
+		t.Errorf("expected ErrInvalidSnapshotFile for a corrupt snapshot file, got %v", err)
+	}
+
+	if hwinfoshmem.NewBytesReader(data).SnapshotFile != nil {
+		t.Error("expected no snapshot file for raw data")
+	}
+}
@@ -0,0 +1,91 @@
+package hwinfoshmem_test
+
+import (
+	"fmt"
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/hwinfoshmem"
+	"testing"
+)
+
+func ExampleNewSnapshot() {
+	var bytesReader = hwinfoshmem.NewBytesReader(data)
+
+	hwInfo, err := bytesReader.GetHeader()
+	if err != nil {
+		fmt.Printf("Failed to get header: %s\n", err)
+		return
+	}
+
+	snapshot, err := hwinfoshmem.NewSnapshot(bytesReader, hwInfo)
+	if err != nil {
+		fmt.Printf("Failed to create snapshot: %s\n", err)
+		return
+	}
+
+	// When using MemoryReader, the lock can be released here.
+
+	for _, sensor := range snapshot.Sensors {
+		if len(sensor.Readings) == 0 {
+			continue
+		}
+
+		fmt.Printf("%q\n", sensor.SensorName)
+		for _, reading := range sensor.Readings {
+			fmt.Printf("  %s: %.2f %s\n", reading.UserLabel, reading.Value, reading.Unit)
+		}
+	}
+
+	// Output:
+	// "CPU [#0]: AMD Ryzen 9 7950X: Enhanced"
+	//   CPU (Tctl/Tdie): 47.25 °C
+	//   CPU Die (average): 45.09 °C
+	//   CPU CCD1 (Tdie): 45.12 °C
+	//   CPU CCD2 (Tdie): 33.38 °C
+	// "GIGABYTE B650E AORUS MASTER (ITE IT8689E)"
+	//   Water (EC_TEMP1): 27.00 °C
+	// "GPU [#0]: AMD Radeon RX 7900 XTX: "
+	//   GPU Memory Junction Temperature: 48.00 °C
+	//   GPU Hot Spot Temperature: 35.00 °C
+}
+
+func TestSnapshotOwnsData(t *testing.T) {
+	dataCopy := make([]byte, len(data))
+	copy(dataCopy, data)
+	bytesReader := hwinfoshmem.NewBytesReader(dataCopy)
+
+	info, err := bytesReader.GetHeader()
+	if err != nil {
+		t.Fatalf("failed to get header: %v", err)
+	}
+
+	snapshot, err := hwinfoshmem.NewSnapshot(bytesReader, info)
+	if err != nil {
+		t.Fatalf("failed to create snapshot: %v", err)
+	}
+
+	clear(dataCopy)
+
+	if !snapshot.Active {
+		t.Errorf("expected snapshot to be active")
+	}
+
+	if snapshot.LastUpdate.Unix() != 1694966200 {
+		t.Errorf("unexpected last update %v", snapshot.LastUpdate)
+	}
+
+	reading := snapshot.Readings[4]
+	if reading.OriginalLabel != "EC_TEMP1" || reading.UserLabel != "Water (EC_TEMP1)" {
+		t.Errorf("unexpected labels %q, %q", reading.OriginalLabel, reading.UserLabel)
+	}
+
+	if reading.Unit != "°C" {
+		t.Errorf("unexpected unit %q", reading.Unit)
+	}
+
+	if reading.Sensor != snapshot.Sensors[6] {
+		t.Errorf("reading not linked to its sensor")
+	}
+
+	if reading.Sensor.SensorNameOriginal != "GIGABYTE B650E AORUS MASTER (ITE IT8689E)" {
+		t.Errorf("unexpected sensor name %q", reading.Sensor.SensorNameOriginal)
+	}
+}
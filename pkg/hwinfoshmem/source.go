@@ -0,0 +1,24 @@
+package hwinfoshmem
+
+import "github.com/MatthiasKunnen/hwinfo-go/pkg/util/bytesutil"
+
+// Source is implemented by the types that extract the header, sensors, and readings from HWiNFO's
+// data.
+//
+// [Reader] converts the data in place, the results point into the underlying memory.
+// [Decoder] copies the data into values that are owned by the caller.
+type Source interface {
+	GetHeader() (*HwinfoHeader, error)
+	GetSensors(info *HwinfoHeader) ([]*HwinfoSensor, error)
+	GetReadings(info *HwinfoHeader) ([]*HwinfoReading, error)
+	GetReadingsById(info *HwinfoHeader, readingIds []ReadingIdSensorCombo) ([]*HwinfoReading, error)
+
+	// GetCodepage returns the codepage used to convert the ASCII strings.
+	GetCodepage() bytesutil.Codepage
+}
+
+var (
+	_ Source = (*Reader)(nil)
+	_ Source = (*Decoder)(nil)
+	_ Source = (*Supervisor)(nil)
+)
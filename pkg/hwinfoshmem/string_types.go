@@ -17,8 +17,28 @@ const (
 //	[System.Text.Encoding]::Default
 //
 // The string it contains is padded by nul bytes.
+// To convert it to a string, use [HwinfoSensorStringAscii.String] or
+// [HwinfoSensorStringAscii.Decode].
 type HwinfoSensorStringAscii [hwinfoSensorStringLength]byte
 
+// String converts the bytes to a string using [SystemCodepage].
+func (s HwinfoSensorStringAscii) String() string {
+	return decodeAscii(s[:], SystemCodepage())
+}
+
+// Decode converts the bytes to a string using the given codepage.
+func (s HwinfoSensorStringAscii) Decode(codepage bytesutil.Codepage) (string, error) {
+	return bytesutil.AnsiBytesToString(s[:], codepage)
+}
+
+// NewHwinfoSensorStringAscii encodes the string using the given codepage.
+// Strings that are too long are truncated.
+func NewHwinfoSensorStringAscii(s string, codepage bytesutil.Codepage) (HwinfoSensorStringAscii, error) {
+	var result HwinfoSensorStringAscii
+	_, err := bytesutil.StringToAnsiBytes(result[:len(result)-1], s, codepage)
+	return result, err
+}
+
 // HwinfoSensorStringUtf8 is a fixed length byte array of UTF-8 encoded characters.
 // The string it contains is padded by nul bytes.
 // To convert it to a string, use [HwinfoSensorStringUtf8.String].
@@ -29,10 +49,36 @@ func (s HwinfoSensorStringUtf8) String() string {
 	return bytesutil.Utf8BytesToString(s[:])
 }
 
+// NewHwinfoSensorStringUtf8 converts the string to HwinfoSensorStringUtf8.
+// Strings that are too long are truncated.
+func NewHwinfoSensorStringUtf8(s string) HwinfoSensorStringUtf8 {
+	var result HwinfoSensorStringUtf8
+	bytesutil.StringToUtf8Bytes(result[:len(result)-1], s)
+	return result
+}
+
 // HwinfoUnitStringAscii is the same as [HwinfoSensorStringAscii] but used for unit strings such as
 // °C and MHz.
 type HwinfoUnitStringAscii [hwinfoUnitStringLength]byte
 
+// String converts the bytes to a string using [SystemCodepage].
+func (s HwinfoUnitStringAscii) String() string {
+	return decodeAscii(s[:], SystemCodepage())
+}
+
+// Decode converts the bytes to a string using the given codepage.
+func (s HwinfoUnitStringAscii) Decode(codepage bytesutil.Codepage) (string, error) {
+	return bytesutil.AnsiBytesToString(s[:], codepage)
+}
+
+// NewHwinfoUnitStringAscii encodes the string using the given codepage.
+// Strings that are too long are truncated.
+func NewHwinfoUnitStringAscii(s string, codepage bytesutil.Codepage) (HwinfoUnitStringAscii, error) {
+	var result HwinfoUnitStringAscii
+	_, err := bytesutil.StringToAnsiBytes(result[:len(result)-1], s, codepage)
+	return result, err
+}
+
 // HwinfoUnitStringUtf8 is the same as  [HwinfoSensorStringUtf8] but used for unit strings such as
 // °C and MHz.
 type HwinfoUnitStringUtf8 [hwinfoUnitStringLength]byte
@@ -40,3 +86,22 @@ type HwinfoUnitStringUtf8 [hwinfoUnitStringLength]byte
 func (s HwinfoUnitStringUtf8) String() string {
 	return bytesutil.Utf8BytesToString(s[:])
 }
+
+// NewHwinfoUnitStringUtf8 converts the string to HwinfoUnitStringUtf8.
+// Strings that are too long are truncated.
+func NewHwinfoUnitStringUtf8(s string) HwinfoUnitStringUtf8 {
+	var result HwinfoUnitStringUtf8
+	bytesutil.StringToUtf8Bytes(result[:len(result)-1], s)
+	return result
+}
+
+// decodeAscii converts the bytes using the codepage, falling back to Windows-1252 when the codepage
+// is not supported.
+func decodeAscii(data []byte, codepage bytesutil.Codepage) string {
+	if !codepage.IsSupported() {
+		codepage = bytesutil.Codepage1252
+	}
+
+	result, _ := bytesutil.AnsiBytesToString(data, codepage)
+	return result
+}
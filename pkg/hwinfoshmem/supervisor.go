@@ -0,0 +1,351 @@
+package hwinfoshmem
+
+import (
+	"errors"
+	"fmt"
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/util/bytesutil"
+	"time"
+)
+
+// ErrDisconnected is returned by [Supervisor] when no backend is open.
+var ErrDisconnected = errors.New("not connected")
+
+// DefaultStaleAfter is the time after which [Supervisor] considers data that is no longer updated
+// stale.
+const DefaultStaleAfter = time.Minute
+
+// Backend is a [Source] that needs to be opened before reading, e.g. [MemoryReader] and
+// [FileReader].
+type Backend interface {
+	Source
+	Locker
+
+	// Open readies the backend for reading.
+	Open() error
+
+	// Close releases the lock and the resources of the backend.
+	Close() error
+}
+
+// SupervisorState is the state of the connection of a [Supervisor] to its backend.
+type SupervisorState int
+
+const (
+	// SupervisorDisconnected means that no backend is open, e.g. because HWiNFO is not running.
+	SupervisorDisconnected SupervisorState = iota
+
+	// SupervisorConnected means that the backend is open and HWiNFO is updating the data.
+	SupervisorConnected
+
+	// SupervisorInactive means that the backend is open but HWiNFO is no longer updating the data,
+	// see [HwinfoHeader.IsActive].
+	SupervisorInactive
+
+	// SupervisorStale means that the backend is open and HWiNFO reports to be active, but the data
+	// has not been updated for longer than [Supervisor.StaleAfter].
+	SupervisorStale
+)
+
+func (state SupervisorState) String() string {
+	switch state {
+	case SupervisorDisconnected:
+		return "disconnected"
+	case SupervisorConnected:
+		return "connected"
+	case SupervisorInactive:
+		return "inactive"
+	case SupervisorStale:
+		return "stale"
+	default:
+		return fmt.Sprintf("SupervisorState(%d)", int(state))
+	}
+}
+
+// StateChange is passed to [Supervisor.OnStateChange].
+type StateChange struct {
+	From SupervisorState
+	To   SupervisorState
+
+	// The error that caused the change, if any.
+	Err error
+}
+
+// Supervisor reads from a [Backend] and replaces it with a newly opened one when HWiNFO has
+// restarted or stopped updating the data.
+// E.g. when HWiNFO's shared memory time limit expires, the status becomes "DAED" and a restarted
+// HWiNFO creates new shared memory which the old handle does not see.
+//
+// The backend is replaced when:
+//   - opening it fails, e.g. because HWiNFO is not running
+//   - acquiring its lock fails, e.g. because the mutex is gone
+//   - reading the header fails, e.g. because the data disappeared
+//   - HWiNFO is inactive
+//   - the data is stale, see [Supervisor.StaleAfter]
+//
+// Between attempts, the Supervisor backs off exponentially from MinBackoff to MaxBackoff.
+// Backing off does not block, reads return an error wrapping [ErrDisconnected] instead.
+//
+// Supervisor implements [Source] and [Locker] and can be used with a [Watcher].
+// Reconnecting is performed by [Supervisor.Lock] so it must be called before every read, also
+// when the backend does not require locking.
+//
+// Supervisor has an initializer function, [NewSupervisor].
+type Supervisor struct {
+	// NewBackend creates the backend that will be opened.
+	// A new backend is created every time the Supervisor reconnects.
+	NewBackend func() Backend
+
+	// MinBackoff is the time to wait after the first failure.
+	MinBackoff time.Duration
+
+	// MaxBackoff is the maximum time to wait between attempts.
+	MaxBackoff time.Duration
+
+	// StaleAfter is the time after the LastUpdate of active data after which the data is
+	// considered stale. When zero, staleness is not checked.
+	StaleAfter time.Duration
+
+	// OnStateChange, when set, is called every time the state changes.
+	OnStateChange func(change StateChange)
+
+	// Now returns the current time. Can be replaced to control time in tests.
+	Now func() time.Time
+
+	backend     Backend
+	state       SupervisorState
+	backoff     time.Duration
+	nextAttempt time.Time
+	lastErr     error
+}
+
+var _ Locker = (*Supervisor)(nil)
+
+// NewSupervisor returns a Supervisor that backs off from 1 second to 30 seconds and considers the
+// data stale after [DefaultStaleAfter].
+//
+// E.g. to read HWiNFO's shared memory on Windows:
+//
+//	NewSupervisor(func() Backend { return NewMemoryReader() })
+func NewSupervisor(newBackend func() Backend) *Supervisor {
+	return &Supervisor{
+		NewBackend: newBackend,
+		MinBackoff: time.Second,
+		MaxBackoff: 30 * time.Second,
+		StaleAfter: DefaultStaleAfter,
+		Now:        time.Now,
+	}
+}
+
+// State returns the current state.
+func (supervisor *Supervisor) State() SupervisorState {
+	return supervisor.state
+}
+
+// Lock opens a new backend when needed and acquires the lock of the backend.
+// Returns an error wrapping [ErrDisconnected] when no backend could be opened or its lock could
+// not be acquired, in which case the backend is closed.
+func (supervisor *Supervisor) Lock() error {
+	if err := supervisor.connect(); err != nil {
+		return err
+	}
+
+	if err := supervisor.backend.Lock(); err != nil {
+		// E.g. HWiNFO restarted and the mutex or file of the backend is gone.
+		supervisor.disconnect(err)
+		return supervisor.disconnectedError()
+	}
+
+	return nil
+}
+
+// ReleaseLock releases the lock of the backend.
+// Returns nil when: the lock is successfully released, the lock was not held, or there is no
+// backend.
+func (supervisor *Supervisor) ReleaseLock() error {
+	if supervisor.backend == nil {
+		return nil
+	}
+
+	return supervisor.backend.ReleaseLock()
+}
+
+// IsLocked reports whether the lock of the backend is held.
+func (supervisor *Supervisor) IsLocked() bool {
+	return supervisor.backend != nil && supervisor.backend.IsLocked()
+}
+
+// Close closes the backend. A new backend is opened by the next call to [Supervisor.Lock].
+func (supervisor *Supervisor) Close() error {
+	if supervisor.backend == nil {
+		return nil
+	}
+
+	err := supervisor.backend.Close()
+	supervisor.backend = nil
+	supervisor.setState(SupervisorDisconnected, nil)
+
+	return err
+}
+
+// GetHeader returns the header of the backend and updates the state based on it.
+// When reading the header fails, the backend is closed.
+func (supervisor *Supervisor) GetHeader() (*HwinfoHeader, error) {
+	if supervisor.backend == nil {
+		return nil, supervisor.disconnectedError()
+	}
+
+	info, err := supervisor.backend.GetHeader()
+	if err != nil {
+		supervisor.disconnect(err)
+		return nil, err
+	}
+
+	switch {
+	case !info.IsActive():
+		supervisor.degrade(SupervisorInactive, nil)
+	case supervisor.StaleAfter > 0 && supervisor.Now().Sub(info.GetLastUpdateTime()) > supervisor.StaleAfter:
+		supervisor.degrade(
+			SupervisorStale,
+			fmt.Errorf("last update at %s", info.GetLastUpdateTime().Format(time.RFC3339)),
+		)
+	default:
+		supervisor.setState(SupervisorConnected, nil)
+	}
+
+	return info, nil
+}
+
+// GetSensors returns the sensors of the backend, see [Reader.GetSensors].
+func (supervisor *Supervisor) GetSensors(info *HwinfoHeader) ([]*HwinfoSensor, error) {
+	if supervisor.backend == nil {
+		return nil, supervisor.disconnectedError()
+	}
+
+	return supervisor.backend.GetSensors(info)
+}
+
+// GetReadings returns the readings of the backend, see [Reader.GetReadings].
+func (supervisor *Supervisor) GetReadings(info *HwinfoHeader) ([]*HwinfoReading, error) {
+	if supervisor.backend == nil {
+		return nil, supervisor.disconnectedError()
+	}
+
+	return supervisor.backend.GetReadings(info)
+}
+
+// GetReadingsById returns the matching readings of the backend, see [Reader.GetReadingsById].
+func (supervisor *Supervisor) GetReadingsById(info *HwinfoHeader, readingIds []ReadingIdSensorCombo) ([]*HwinfoReading, error) {
+	if supervisor.backend == nil {
+		return nil, supervisor.disconnectedError()
+	}
+
+	return supervisor.backend.GetReadingsById(info, readingIds)
+}
+
+// GetCodepage returns the codepage of the backend, or [SystemCodepage] when there is no backend.
+func (supervisor *Supervisor) GetCodepage() bytesutil.Codepage {
+	if supervisor.backend == nil {
+		return SystemCodepage()
+	}
+
+	return supervisor.backend.GetCodepage()
+}
+
+// connect makes sure a backend is open, replacing it when it is inactive or stale and the backoff
+// has passed.
+func (supervisor *Supervisor) connect() error {
+	if supervisor.state == SupervisorConnected {
+		return nil
+	}
+
+	now := supervisor.Now()
+	if now.Before(supervisor.nextAttempt) {
+		if supervisor.backend != nil {
+			// Keep reading the inactive or stale backend until it is time to try again.
+			return nil
+		}
+
+		return supervisor.disconnectedError()
+	}
+
+	if supervisor.backend != nil {
+		// Errors closing the old backend do not matter, it is replaced.
+		_ = supervisor.backend.Close()
+		supervisor.backend = nil
+	}
+
+	backend := supervisor.NewBackend()
+	if err := backend.Open(); err != nil {
+		supervisor.disconnect(err)
+		return supervisor.disconnectedError()
+	}
+
+	supervisor.backend = backend
+	// The state is determined by the next header, until then, wait before replacing it again.
+	supervisor.scheduleAttempt()
+
+	return nil
+}
+
+// disconnect closes the backend after a failure and schedules the next attempt.
+func (supervisor *Supervisor) disconnect(err error) {
+	if supervisor.backend != nil {
+		_ = supervisor.backend.Close()
+		supervisor.backend = nil
+	}
+
+	supervisor.lastErr = err
+	supervisor.scheduleAttempt()
+	supervisor.setState(SupervisorDisconnected, err)
+}
+
+// degrade changes the state to inactive or stale and schedules replacing the backend when it was
+// connected.
+func (supervisor *Supervisor) degrade(state SupervisorState, err error) {
+	if supervisor.state == SupervisorConnected {
+		supervisor.scheduleAttempt()
+	}
+
+	supervisor.setState(state, err)
+}
+
+func (supervisor *Supervisor) scheduleAttempt() {
+	if supervisor.backoff == 0 {
+		supervisor.backoff = supervisor.MinBackoff
+	} else {
+		supervisor.backoff = min(2*supervisor.backoff, supervisor.MaxBackoff)
+	}
+
+	supervisor.nextAttempt = supervisor.Now().Add(supervisor.backoff)
+}
+
+func (supervisor *Supervisor) setState(state SupervisorState, err error) {
+	if state == SupervisorConnected {
+		supervisor.backoff = 0
+		supervisor.lastErr = nil
+	}
+
+	if state == supervisor.state {
+		return
+	}
+
+	change := StateChange{From: supervisor.state, To: state, Err: err}
+	supervisor.state = state
+
+	if supervisor.OnStateChange != nil {
+		supervisor.OnStateChange(change)
+	}
+}
+
+func (supervisor *Supervisor) disconnectedError() error {
+	if supervisor.lastErr == nil {
+		return ErrDisconnected
+	}
+
+	return fmt.Errorf(
+		"%w, retrying in %s: %w",
+		ErrDisconnected,
+		supervisor.nextAttempt.Sub(supervisor.Now()).Round(time.Millisecond),
+		supervisor.lastErr,
+	)
+}
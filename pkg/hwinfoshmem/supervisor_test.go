@@ -0,0 +1,242 @@
+package hwinfoshmem_test
+
+import (
+	"errors"
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/hwinfoshmem"
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/hwinfosim"
+	"io/fs"
+	"slices"
+	"testing"
+	"time"
+)
+
+// fakeSystem simulates HWiNFO's shared memory appearing and disappearing.
+type fakeSystem struct {
+	present bool
+	lockErr error
+	target  *hwinfosim.MemoryTarget
+	opened  int
+	closed  int
+}
+
+// fakeBackend reads from a fakeSystem like a MemoryReader.
+type fakeBackend struct {
+	system *fakeSystem
+	locked bool
+	*targetSource
+}
+
+func (system *fakeSystem) newBackend() hwinfoshmem.Backend {
+	return &fakeBackend{
+		system:       system,
+		targetSource: &targetSource{target: system.target, Decoder: hwinfoshmem.NewDecoder(nil)},
+	}
+}
+
+func (backend *fakeBackend) Open() error {
+	if !backend.system.present {
+		return fs.ErrNotExist
+	}
+
+	backend.system.opened++
+	return nil
+}
+
+func (backend *fakeBackend) Close() error {
+	backend.locked = false
+	backend.system.closed++
+	return nil
+}
+
+func (backend *fakeBackend) Lock() error {
+	if backend.system.lockErr != nil {
+		return backend.system.lockErr
+	}
+
+	backend.locked = true
+	return nil
+}
+
+func (backend *fakeBackend) ReleaseLock() error {
+	backend.locked = false
+	return nil
+}
+
+func (backend *fakeBackend) IsLocked() bool {
+	return backend.locked
+}
+
+func (backend *fakeBackend) GetHeader() (*hwinfoshmem.HwinfoHeader, error) {
+	if !backend.system.present {
+		return nil, errors.New("segment unmapped")
+	}
+
+	return backend.targetSource.GetHeader()
+}
+
+func TestSupervisor(t *testing.T) {
+	now := time.Unix(1694966200, 0)
+	system := &fakeSystem{target: &hwinfosim.MemoryTarget{}}
+	simulator := hwinfosim.NewSimulator(system.target)
+	simulator.Now = func() time.Time {
+		return now
+	}
+
+	supervisor := hwinfoshmem.NewSupervisor(system.newBackend)
+	supervisor.Now = simulator.Now
+	var changes []hwinfoshmem.StateChange
+	supervisor.OnStateChange = func(change hwinfoshmem.StateChange) {
+		changes = append(changes, change)
+	}
+
+	// read performs a read cycle and returns the resulting state.
+	read := func() (hwinfoshmem.SupervisorState, error) {
+		t.Helper()
+
+		if err := supervisor.Lock(); err != nil {
+			return supervisor.State(), err
+		}
+		defer supervisor.ReleaseLock()
+
+		info, err := supervisor.GetHeader()
+		if err != nil {
+			return supervisor.State(), err
+		}
+
+		if _, err = hwinfoshmem.NewSnapshot(supervisor, info); err != nil {
+			t.Fatalf("failed to create snapshot: %v", err)
+		}
+
+		return supervisor.State(), nil
+	}
+
+	if _, err := read(); !errors.Is(err, hwinfoshmem.ErrDisconnected) || !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("expected disconnected error wrapping the open error, got %v", err)
+	}
+
+	// HWiNFO starts but the backoff has not passed yet.
+	system.present = true
+	if err := simulator.Update(); err != nil {
+		t.Fatalf("failed to update: %v", err)
+	}
+
+	if _, err := read(); !errors.Is(err, hwinfoshmem.ErrDisconnected) || system.opened != 0 {
+		t.Fatalf("expected no attempt during backoff, got %v", err)
+	}
+
+	now = now.Add(time.Second)
+	if err := simulator.Update(); err != nil {
+		t.Fatalf("failed to update: %v", err)
+	}
+
+	if state, err := read(); state != hwinfoshmem.SupervisorConnected || err != nil {
+		t.Fatalf("expected connected, got %v %v", state, err)
+	}
+
+	// The shared memory time limit expires.
+	if err := simulator.SetActive(false); err != nil {
+		t.Fatalf("failed to deactivate: %v", err)
+	}
+
+	if state, err := read(); state != hwinfoshmem.SupervisorInactive || err != nil {
+		t.Fatalf("expected inactive, got %v %v", state, err)
+	}
+
+	// HWiNFO restarts, the backend is replaced once the backoff has passed.
+	now = now.Add(time.Second)
+	if err := simulator.SetActive(true); err != nil {
+		t.Fatalf("failed to activate: %v", err)
+	}
+	if err := simulator.Update(); err != nil {
+		t.Fatalf("failed to update: %v", err)
+	}
+
+	if state, err := read(); state != hwinfoshmem.SupervisorConnected || err != nil {
+		t.Fatalf("expected connected, got %v %v", state, err)
+	}
+
+	if system.opened != 2 || system.closed != 1 {
+		t.Errorf("expected backend to be replaced, opened %d, closed %d", system.opened, system.closed)
+	}
+
+	// HWiNFO stops updating without changing its status.
+	now = now.Add(2 * time.Minute)
+	if state, err := read(); state != hwinfoshmem.SupervisorStale || err != nil {
+		t.Fatalf("expected stale, got %v %v", state, err)
+	}
+
+	// The shared memory disappears.
+	system.present = false
+	now = now.Add(time.Minute)
+	if state, err := read(); state != hwinfoshmem.SupervisorDisconnected || err == nil {
+		t.Fatalf("expected disconnected with error, got %v %v", state, err)
+	}
+
+	expected := []hwinfoshmem.SupervisorState{
+		hwinfoshmem.SupervisorConnected,
+		hwinfoshmem.SupervisorInactive,
+		hwinfoshmem.SupervisorConnected,
+		hwinfoshmem.SupervisorStale,
+		hwinfoshmem.SupervisorDisconnected,
+	}
+	states := make([]hwinfoshmem.SupervisorState, len(changes))
+	for i, change := range changes {
+		states[i] = change.To
+	}
+
+	if !slices.Equal(states, expected) {
+		t.Errorf("expected state changes %v, got %v", expected, states)
+	}
+}
+
+func TestSupervisorLockFails(t *testing.T) {
+	now := time.Unix(1694966200, 0)
+	system := &fakeSystem{present: true, target: &hwinfosim.MemoryTarget{}}
+	simulator := hwinfosim.NewSimulator(system.target)
+	simulator.Now = func() time.Time {
+		return now
+	}
+	if err := simulator.Update(); err != nil {
+		t.Fatalf("failed to update: %v", err)
+	}
+
+	supervisor := hwinfoshmem.NewSupervisor(system.newBackend)
+	supervisor.Now = simulator.Now
+
+	read := func() error {
+		if err := supervisor.Lock(); err != nil {
+			return err
+		}
+		defer supervisor.ReleaseLock()
+
+		_, err := supervisor.GetHeader()
+		return err
+	}
+
+	if err := read(); err != nil || supervisor.State() != hwinfoshmem.SupervisorConnected {
+		t.Fatalf("expected connected, got %v %v", supervisor.State(), err)
+	}
+
+	// HWiNFO exits and its mutex is gone.
+	lockErr := errors.New("mutex abandoned")
+	system.lockErr = lockErr
+	err := read()
+	if !errors.Is(err, hwinfoshmem.ErrDisconnected) || !errors.Is(err, lockErr) {
+		t.Fatalf("expected disconnected error wrapping the lock error, got %v", err)
+	}
+
+	if supervisor.State() != hwinfoshmem.SupervisorDisconnected || system.closed != 1 {
+		t.Fatalf("expected the backend to be closed, got %v, closed %d", supervisor.State(), system.closed)
+	}
+
+	// HWiNFO restarts, a new backend is opened once the backoff has passed.
+	system.lockErr = nil
+	now = now.Add(time.Second)
+	if err = read(); err != nil || supervisor.State() != hwinfoshmem.SupervisorConnected {
+		t.Fatalf("expected connected, got %v %v", supervisor.State(), err)
+	}
+
+	if system.opened != 2 {
+		t.Errorf("expected a new backend to be opened, opened %d", system.opened)
+	}
+}
@@ -0,0 +1,157 @@
+package hwinfoshmem
+
+// SensorKey identifies a sensor independently of its position in the sensor section.
+type SensorKey struct {
+	SensorId       uint32
+	SensorInstance uint32
+}
+
+// SensorChange contains a sensor as it was in the old and the new snapshot.
+type SensorChange struct {
+	Old *Sensor
+	New *Sensor
+}
+
+// ReadingChange contains a reading as it was in the old and the new snapshot.
+type ReadingChange struct {
+	Old *Reading
+	New *Reading
+}
+
+// TopologyDiff describes how the sensors and readings of two snapshots differ, see
+// [DiffTopology].
+type TopologyDiff struct {
+	// Sensors of the new snapshot that did not exist in the old snapshot.
+	AddedSensors []*Sensor
+
+	// Sensors of the old snapshot that no longer exist in the new snapshot.
+	RemovedSensors []*Sensor
+
+	// Sensors of which the name or original name changed.
+	RenamedSensors []SensorChange
+
+	// Sensors of which the index changed, e.g. because a sensor before it was removed.
+	// The readings of moved sensors are not part of AddedReadings and RemovedReadings as they
+	// are identified by their [ReadingKey].
+	MovedSensors []SensorChange
+
+	// Readings of the new snapshot that did not exist in the old snapshot.
+	AddedReadings []*Reading
+
+	// Readings of the old snapshot that no longer exist in the new snapshot.
+	RemovedReadings []*Reading
+
+	// Readings of which the user label, original label, or unit changed.
+	RenamedReadings []ReadingChange
+
+	// Whether the size of each sensor, as reported by the header, changed.
+	SensorSizeChanged bool
+
+	// Whether the size of each reading, as reported by the header, changed.
+	ReadingSizeChanged bool
+}
+
+// HasChanges reports whether the topology differs.
+func (diff *TopologyDiff) HasChanges() bool {
+	return len(diff.AddedSensors) > 0 ||
+		len(diff.RemovedSensors) > 0 ||
+		len(diff.RenamedSensors) > 0 ||
+		len(diff.MovedSensors) > 0 ||
+		len(diff.AddedReadings) > 0 ||
+		len(diff.RemovedReadings) > 0 ||
+		len(diff.RenamedReadings) > 0 ||
+		diff.SensorSizeChanged ||
+		diff.ReadingSizeChanged
+}
+
+// DiffTopology compares the sensors and readings of an old snapshot, before, with those of a new
+// snapshot, after, ignoring their values.
+// Sensors are identified by their SensorId and SensorInstance, readings by their [ReadingKey],
+// see [Reading.Key].
+// When an identifier occurs multiple times in a snapshot, only the first occurrence is compared.
+//
+// Use it to detect that HWiNFO added or removed sensors, e.g. to rebuild anything that refers to
+// sensors or readings by index.
+func DiffTopology(before *Snapshot, after *Snapshot) TopologyDiff {
+	diff := TopologyDiff{
+		SensorSizeChanged:  before.Layout.SensorSize != after.Layout.SensorSize,
+		ReadingSizeChanged: before.Layout.ReadingSize != after.Layout.ReadingSize,
+	}
+
+	oldSensors := make(map[SensorKey]*Sensor, len(before.Sensors))
+	for _, sensor := range before.Sensors {
+		key := SensorKey{SensorId: sensor.SensorId, SensorInstance: sensor.SensorInstance}
+		if _, exists := oldSensors[key]; !exists {
+			oldSensors[key] = sensor
+		}
+	}
+
+	newSensors := make(map[SensorKey]*Sensor, len(after.Sensors))
+	for _, sensor := range after.Sensors {
+		key := SensorKey{SensorId: sensor.SensorId, SensorInstance: sensor.SensorInstance}
+		if _, exists := newSensors[key]; exists {
+			continue
+		}
+		newSensors[key] = sensor
+
+		oldSensor, exists := oldSensors[key]
+		if !exists {
+			diff.AddedSensors = append(diff.AddedSensors, sensor)
+			continue
+		}
+
+		change := SensorChange{Old: oldSensor, New: sensor}
+		if oldSensor.SensorName != sensor.SensorName || oldSensor.SensorNameOriginal != sensor.SensorNameOriginal {
+			diff.RenamedSensors = append(diff.RenamedSensors, change)
+		}
+
+		if oldSensor.Index != sensor.Index {
+			diff.MovedSensors = append(diff.MovedSensors, change)
+		}
+	}
+
+	for _, sensor := range before.Sensors {
+		key := SensorKey{SensorId: sensor.SensorId, SensorInstance: sensor.SensorInstance}
+		if _, exists := newSensors[key]; !exists && oldSensors[key] == sensor {
+			diff.RemovedSensors = append(diff.RemovedSensors, sensor)
+		}
+	}
+
+	oldReadings := make(map[ReadingKey]*Reading, len(before.Readings))
+	for _, reading := range before.Readings {
+		key := reading.Key()
+		if _, exists := oldReadings[key]; !exists {
+			oldReadings[key] = reading
+		}
+	}
+
+	newReadings := make(map[ReadingKey]bool, len(after.Readings))
+	for _, reading := range after.Readings {
+		key := reading.Key()
+		if newReadings[key] {
+			continue
+		}
+		newReadings[key] = true
+
+		oldReading, exists := oldReadings[key]
+		if !exists {
+			diff.AddedReadings = append(diff.AddedReadings, reading)
+			continue
+		}
+
+		if oldReading.UserLabel != reading.UserLabel ||
+			oldReading.OriginalLabel != reading.OriginalLabel ||
+			oldReading.Unit != reading.Unit {
+			diff.RenamedReadings = append(diff.RenamedReadings, ReadingChange{Old: oldReading, New: reading})
+		}
+	}
+
+	for _, reading := range before.Readings {
+		key := reading.Key()
+		if !newReadings[key] && oldReadings[key] == reading {
+			diff.RemovedReadings = append(diff.RemovedReadings, reading)
+		}
+	}
+
+	return diff
+}
@@ -0,0 +1,141 @@
+package hwinfoshmem_test
+
+import (
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/hwinfoshmem"
+	"testing"
+)
+
+func newTestSnapshot(t *testing.T) *hwinfoshmem.Snapshot {
+	t.Helper()
+
+	bytesReader := hwinfoshmem.NewBytesReader(data)
+	info, err := bytesReader.GetHeader()
+	if err != nil {
+		t.Fatalf("failed to get header: %v", err)
+	}
+
+	snapshot, err := hwinfoshmem.NewSnapshot(bytesReader, info)
+	if err != nil {
+		t.Fatalf("failed to create snapshot: %v", err)
+	}
+
+	return snapshot
+}
+
+func TestDiffTopologyUnchanged(t *testing.T) {
+	old := newTestSnapshot(t)
+	updated := newTestSnapshot(t)
+	updated.Readings[0].Value++
+
+	if diff := hwinfoshmem.DiffTopology(old, updated); diff.HasChanges() {
+		t.Errorf("expected no changes, got %+v", diff)
+	}
+}
+
+func TestDiffTopology(t *testing.T) {
+	old := newTestSnapshot(t)
+	updated := newTestSnapshot(t)
+
+	// Replace the last sensor with a new sensor.
+	removedSensor := updated.Sensors[27]
+	updated.Sensors = updated.Sensors[:27]
+	updated.Sensors = append(updated.Sensors, &hwinfoshmem.Sensor{Index: 27, SensorId: 0xF0001000})
+
+	// Remove the last two readings, which belong to the GPU.
+	updated.Readings = updated.Readings[:5]
+	updated.Sensors[6].SensorName = "Motherboard"
+	updated.Readings[4].UserLabel = "Water"
+	updated.Readings = append(updated.Readings, &hwinfoshmem.Reading{Sensor: updated.Sensors[27], SensorIndex: 27, Id: 1})
+	updated.Layout.ReadingSize += 8
+
+	diff := hwinfoshmem.DiffTopology(old, updated)
+
+	if len(diff.AddedSensors) != 1 || diff.AddedSensors[0].SensorId != 0xF0001000 {
+		t.Errorf("unexpected added sensors %+v", diff.AddedSensors)
+	}
+
+	if len(diff.RemovedSensors) != 1 || diff.RemovedSensors[0].SensorId != removedSensor.SensorId {
+		t.Errorf("unexpected removed sensors %+v", diff.RemovedSensors)
+	}
+
+	if len(diff.RenamedSensors) != 1 || diff.RenamedSensors[0].New.SensorName != "Motherboard" {
+		t.Errorf("unexpected renamed sensors %+v", diff.RenamedSensors)
+	}
+
+	if len(diff.MovedSensors) != 0 {
+		t.Errorf("unexpected moved sensors %+v", diff.MovedSensors)
+	}
+
+	if len(diff.AddedReadings) != 1 || diff.AddedReadings[0].SensorIndex != 27 {
+		t.Errorf("unexpected added readings %+v", diff.AddedReadings)
+	}
+
+	if len(diff.RemovedReadings) != 2 || diff.RemovedReadings[0] != old.Readings[5] {
+		t.Errorf("unexpected removed readings %+v", diff.RemovedReadings)
+	}
+
+	if len(diff.RenamedReadings) != 1 || diff.RenamedReadings[0].Old.UserLabel != "Water (EC_TEMP1)" {
+		t.Errorf("unexpected renamed readings %+v", diff.RenamedReadings)
+	}
+
+	if diff.SensorSizeChanged || !diff.ReadingSizeChanged {
+		t.Errorf("expected only the reading size to change")
+	}
+}
+
+func TestDiffTopologyMovedSensor(t *testing.T) {
+	old := newTestSnapshot(t)
+	updated := newTestSnapshot(t)
+
+	// Removing the first sensor moves all other sensors up.
+	updated.Sensors = updated.Sensors[1:]
+	for i, sensor := range updated.Sensors {
+		sensor.Index = uint32(i)
+	}
+	for _, reading := range updated.Readings {
+		reading.SensorIndex--
+	}
+
+	diff := hwinfoshmem.DiffTopology(old, updated)
+
+	if len(diff.RemovedSensors) != 1 || len(diff.MovedSensors) != 27 {
+		t.Errorf("expected 1 removed and 27 moved sensors, got %d and %d", len(diff.RemovedSensors), len(diff.MovedSensors))
+	}
+
+	if len(diff.AddedReadings) != 0 || len(diff.RemovedReadings) != 0 {
+		t.Errorf("expected the readings to be unchanged, got %d added and %d removed", len(diff.AddedReadings), len(diff.RemovedReadings))
+	}
+}
+
+func TestDiffTopologyInsertedSensor(t *testing.T) {
+	old := newTestSnapshot(t)
+	updated := newTestSnapshot(t)
+
+	// HWiNFO adds a sensor before all others with a reading Id that other sensors also use.
+	inserted := &hwinfoshmem.Sensor{SensorId: 0xF0001000, SensorName: "Inserted"}
+	reading := &hwinfoshmem.Reading{Sensor: inserted, Id: updated.Readings[0].Id, UserLabel: "Inserted"}
+	inserted.Readings = []*hwinfoshmem.Reading{reading}
+
+	updated.Sensors = append([]*hwinfoshmem.Sensor{inserted}, updated.Sensors...)
+	for i, sensor := range updated.Sensors {
+		sensor.Index = uint32(i)
+	}
+	for _, reading := range updated.Readings {
+		reading.SensorIndex++
+	}
+	updated.Readings = append([]*hwinfoshmem.Reading{reading}, updated.Readings...)
+
+	diff := hwinfoshmem.DiffTopology(old, updated)
+
+	if len(diff.AddedSensors) != 1 || len(diff.MovedSensors) != 28 {
+		t.Errorf("expected 1 added and 28 moved sensors, got %d and %d", len(diff.AddedSensors), len(diff.MovedSensors))
+	}
+
+	if len(diff.AddedReadings) != 1 || diff.AddedReadings[0] != reading {
+		t.Errorf("expected only the inserted reading to be added, got %+v", diff.AddedReadings)
+	}
+
+	if len(diff.RemovedReadings) != 0 || len(diff.RenamedReadings) != 0 {
+		t.Errorf("expected no removed or renamed readings, got %+v and %+v", diff.RemovedReadings, diff.RenamedReadings)
+	}
+}
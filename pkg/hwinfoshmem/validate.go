@@ -0,0 +1,131 @@
+package hwinfoshmem
+
+import (
+	"errors"
+	"fmt"
+	"unsafe"
+)
+
+var (
+	// ErrTruncated is returned when the data is shorter than what the header describes.
+	ErrTruncated = errors.New("data is truncated")
+
+	// ErrBadSectionOffset is returned when the sensor or reading section starts inside the header
+	// or when the sections overlap.
+	ErrBadSectionOffset = errors.New("bad section offset")
+
+	// ErrUnsupportedSize is returned when the size of a sensor or reading, as reported by the
+	// header, is smaller than the struct it should be converted into.
+	ErrUnsupportedSize = errors.New("unsupported element size")
+)
+
+const (
+	headerSize  = uint64(unsafe.Sizeof(HwinfoHeader{}))
+	sensorSize  = uint64(unsafe.Sizeof(HwinfoSensor{}))
+	readingSize = uint64(unsafe.Sizeof(HwinfoReading{}))
+)
+
+// elementSizes contains the minimum amount of bytes the header, each sensor, and each reading
+// must have.
+type elementSizes struct {
+	header  uint64
+	sensor  uint64
+	reading uint64
+}
+
+// structSizes are the sizes needed to convert the data into the structs in place.
+var structSizes = elementSizes{
+	header:  headerSize,
+	sensor:  sensorSize,
+	reading: readingSize,
+}
+
+// ValidateHeader checks whether the sensor and reading sections described by the header fit in
+// data of the given size and whether their elements can be converted into [HwinfoSensor] and
+// [HwinfoReading].
+// The returned error wraps [ErrTruncated], [ErrBadSectionOffset], or [ErrUnsupportedSize].
+//
+// size is the amount of bytes available starting at the beginning of the header.
+func ValidateHeader(info *HwinfoHeader, size uint64) error {
+	return validateHeader(info, size, structSizes)
+}
+
+func validateHeader(info *HwinfoHeader, size uint64, minimum elementSizes) error {
+	if err := validateLayout(info, minimum); err != nil {
+		return err
+	}
+
+	if size < minimum.header {
+		return fmt.Errorf("%w: header needs %d bytes, got %d", ErrTruncated, minimum.header, size)
+	}
+
+	if end := sensorSectionEnd(info); end > size {
+		return fmt.Errorf("%w: sensor section ends at %d, got %d bytes", ErrTruncated, end, size)
+	}
+
+	if end := readingSectionEnd(info); end > size {
+		return fmt.Errorf("%w: reading section ends at %d, got %d bytes", ErrTruncated, end, size)
+	}
+
+	return nil
+}
+
+// validateLayout performs the checks of validateHeader that do not depend on the size of the data.
+func validateLayout(info *HwinfoHeader, minimum elementSizes) error {
+	if info.SensorAmount > 0 {
+		if uint64(info.SensorSize) < minimum.sensor {
+			return fmt.Errorf(
+				"%w: sensor size is %d, need at least %d",
+				ErrUnsupportedSize,
+				info.SensorSize,
+				minimum.sensor,
+			)
+		}
+
+		if uint64(info.SensorSectionOffset) < minimum.header {
+			return fmt.Errorf(
+				"%w: sensor section starts at %d, inside the header",
+				ErrBadSectionOffset,
+				info.SensorSectionOffset,
+			)
+		}
+	}
+
+	if info.ReadingAmount > 0 {
+		if uint64(info.ReadingSize) < minimum.reading {
+			return fmt.Errorf(
+				"%w: reading size is %d, need at least %d",
+				ErrUnsupportedSize,
+				info.ReadingSize,
+				minimum.reading,
+			)
+		}
+
+		if uint64(info.ReadingSectionOffset) < minimum.header {
+			return fmt.Errorf(
+				"%w: reading section starts at %d, inside the header",
+				ErrBadSectionOffset,
+				info.ReadingSectionOffset,
+			)
+		}
+	}
+
+	if info.SensorAmount > 0 && info.ReadingAmount > 0 &&
+		uint64(info.SensorSectionOffset) < readingSectionEnd(info) &&
+		uint64(info.ReadingSectionOffset) < sensorSectionEnd(info) {
+		return fmt.Errorf("%w: sensor and reading sections overlap", ErrBadSectionOffset)
+	}
+
+	return nil
+}
+
+// sensorSectionEnd returns the offset of the first byte after the sensor section.
+// The calculation is done using uint64 which cannot overflow given that all operands are uint32.
+func sensorSectionEnd(info *HwinfoHeader) uint64 {
+	return uint64(info.SensorSectionOffset) + uint64(info.SensorAmount)*uint64(info.SensorSize)
+}
+
+// readingSectionEnd returns the offset of the first byte after the reading section.
+func readingSectionEnd(info *HwinfoHeader) uint64 {
+	return uint64(info.ReadingSectionOffset) + uint64(info.ReadingAmount)*uint64(info.ReadingSize)
+}
@@ -0,0 +1,90 @@
+package hwinfoshmem_test
+
+import (
+	"encoding/binary"
+	"errors"
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/hwinfoshmem"
+	"testing"
+)
+
+// Offsets of the header fields in the shared memory.
+const (
+	offsetLastUpdate           = 12
+	offsetSensorSectionOffset  = 20
+	offsetSensorSize           = 24
+	offsetSensorAmount         = 28
+	offsetReadingSectionOffset = 32
+	offsetReadingSize          = 36
+	offsetReadingAmount        = 40
+)
+
+func withHeaderField(offset int, value uint32) []byte {
+	modified := make([]byte, len(data))
+	copy(modified, data)
+	binary.LittleEndian.PutUint32(modified[offset:], value)
+	return modified
+}
+
+func TestReaderValidation(t *testing.T) {
+	tests := []struct {
+		name  string
+		bytes []byte
+		err   error
+	}{
+		{"empty", []byte{}, hwinfoshmem.ErrTruncated},
+		{"partial header", data[:20], hwinfoshmem.ErrTruncated},
+		{"header only", data[:48], hwinfoshmem.ErrTruncated},
+		{"missing last reading byte", data[:len(data)-1], hwinfoshmem.ErrTruncated},
+		{"sensor amount too large", withHeaderField(offsetSensorAmount, 0xFFFFFFFF), hwinfoshmem.ErrBadSectionOffset},
+		{"reading amount too large", withHeaderField(offsetReadingAmount, 0xFFFFFFFF), hwinfoshmem.ErrTruncated},
+		{"reading offset too large", withHeaderField(offsetReadingSectionOffset, 0xFFFFFFFF), hwinfoshmem.ErrTruncated},
+		{"sensor offset inside header", withHeaderField(offsetSensorSectionOffset, 8), hwinfoshmem.ErrBadSectionOffset},
+		{"reading offset inside header", withHeaderField(offsetReadingSectionOffset, 0), hwinfoshmem.ErrBadSectionOffset},
+		{"sections overlap", withHeaderField(offsetReadingSectionOffset, 48+392), hwinfoshmem.ErrBadSectionOffset},
+		{"sensor size too small", withHeaderField(offsetSensorSize, 264), hwinfoshmem.ErrUnsupportedSize},
+		{"reading size too small", withHeaderField(offsetReadingSize, 12), hwinfoshmem.ErrUnsupportedSize},
+		{"reading size too large", withHeaderField(offsetReadingSize, 0xFFFFFFFF), hwinfoshmem.ErrTruncated},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			reader := hwinfoshmem.NewBytesReader(test.bytes)
+
+			info, err := reader.GetHeader()
+			if err == nil {
+				_, err = reader.GetSensors(info)
+				if err == nil {
+					_, err = reader.GetReadings(info)
+				}
+			}
+
+			if !errors.Is(err, test.err) {
+				t.Errorf("expected error %v, got %v", test.err, err)
+			}
+		})
+	}
+}
+
+func TestReaderValidationValid(t *testing.T) {
+	reader := hwinfoshmem.NewBytesReader(data)
+
+	info, err := reader.GetHeader()
+	if err != nil {
+		t.Fatalf("failed to get header: %v", err)
+	}
+
+	if err = hwinfoshmem.ValidateHeader(info, uint64(len(data))); err != nil {
+		t.Errorf("expected valid header, got %v", err)
+	}
+
+	readings, err := reader.GetReadingsById(info, []hwinfoshmem.ReadingIdSensorCombo{
+		{Id: 0x1000000, SensorIndex: 4},
+	})
+	if err != nil {
+		t.Fatalf("failed to get readings by id: %v", err)
+	}
+
+	if len(readings) != 1 {
+		t.Errorf("expected 1 reading, got %d", len(readings))
+	}
+}
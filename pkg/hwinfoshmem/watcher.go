@@ -0,0 +1,183 @@
+package hwinfoshmem
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DefaultPollingPeriod is the interval used by [Watcher] when no interval is configured and the
+// header does not contain the polling period of HWiNFO.
+const DefaultPollingPeriod = 2 * time.Second
+
+// WatchEventKind describes what happened in a [WatchEvent].
+type WatchEventKind int
+
+const (
+	// WatchEventSnapshot is sent when the data was updated, i.e. LastUpdate changed.
+	WatchEventSnapshot WatchEventKind = iota
+
+	// WatchEventInactive is sent when HWiNFO stopped updating the data, see
+	// [HwinfoHeader.IsActive].
+	WatchEventInactive
+
+	// WatchEventActive is sent when HWiNFO resumed updating the data after being inactive.
+	WatchEventActive
+
+	// WatchEventError is sent when reading the data failed. The watcher keeps polling.
+	WatchEventError
+)
+
+func (kind WatchEventKind) String() string {
+	switch kind {
+	case WatchEventSnapshot:
+		return "snapshot"
+	case WatchEventInactive:
+		return "inactive"
+	case WatchEventActive:
+		return "active"
+	case WatchEventError:
+		return "error"
+	default:
+		return fmt.Sprintf("WatchEventKind(%d)", int(kind))
+	}
+}
+
+// WatchEvent is sent by [Watcher.Watch].
+type WatchEvent struct {
+	Kind WatchEventKind
+
+	// The data at the time of the event. Nil for WatchEventError.
+	// The snapshot is owned by the receiver.
+	Snapshot *Snapshot
+
+	// The reason reading failed. Only set for WatchEventError.
+	Err error
+}
+
+// Watcher polls a [Source] and sends a [WatchEvent] every time HWiNFO updated the data or changed
+// its status.
+// When the source implements [Locker], e.g. [MemoryReader] and [FileReader], the lock is held
+// only while creating the snapshot.
+//
+// The source must be ready to read, e.g. call [MemoryReader.Open] before watching, and should not
+// be used by others while it is being watched.
+//
+// Watcher has an initializer function, [NewWatcher].
+type Watcher struct {
+	// Source to read the data from.
+	Source Source
+
+	// Interval between polls.
+	// When zero, the polling period reported by HWiNFO is used, or [DefaultPollingPeriod] when
+	// the header does not contain it.
+	Interval time.Duration
+}
+
+func NewWatcher(source Source) *Watcher {
+	return &Watcher{
+		Source: source,
+	}
+}
+
+// Watch starts polling the source in a new goroutine and returns the channel on which the events
+// are sent. Polls that find an unchanged LastUpdate do not result in an event.
+// The first poll is performed immediately.
+//
+// The channel is closed after the context is done. Events are not dropped, polling waits until
+// the previous event is received.
+func (watcher *Watcher) Watch(ctx context.Context) <-chan WatchEvent {
+	events := make(chan WatchEvent)
+
+	go func() {
+		defer close(events)
+
+		state := watchState{active: true, pollingPeriod: DefaultPollingPeriod}
+		timer := time.NewTimer(0)
+		defer timer.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-timer.C:
+			}
+
+			for _, event := range watcher.poll(&state) {
+				select {
+				case <-ctx.Done():
+					return
+				case events <- event:
+				}
+			}
+
+			if watcher.Interval > 0 {
+				timer.Reset(watcher.Interval)
+			} else {
+				timer.Reset(state.pollingPeriod)
+			}
+		}
+	}()
+
+	return events
+}
+
+// watchState is what Watcher remembers between polls.
+type watchState struct {
+	active     bool
+	lastUpdate time.Time
+
+	// pollingPeriod is the last polling period reported by HWiNFO.
+	pollingPeriod time.Duration
+}
+
+// poll reads the source and returns the events that resulted from the changes since the previous
+// poll.
+func (watcher *Watcher) poll(state *watchState) []WatchEvent {
+	snapshot, err := watcher.readSnapshot()
+	if err != nil {
+		return []WatchEvent{{Kind: WatchEventError, Err: err}}
+	}
+
+	if snapshot.PollingPeriod > 0 {
+		state.pollingPeriod = snapshot.PollingPeriod
+	}
+
+	events := make([]WatchEvent, 0, 2)
+
+	if snapshot.Active != state.active {
+		state.active = snapshot.Active
+		kind := WatchEventInactive
+		if snapshot.Active {
+			kind = WatchEventActive
+		}
+		events = append(events, WatchEvent{Kind: kind, Snapshot: snapshot})
+	}
+
+	if snapshot.Active && !snapshot.LastUpdate.Equal(state.lastUpdate) {
+		state.lastUpdate = snapshot.LastUpdate
+		events = append(events, WatchEvent{Kind: WatchEventSnapshot, Snapshot: snapshot})
+	}
+
+	return events
+}
+
+// readSnapshot creates a snapshot of the source while holding its lock.
+func (watcher *Watcher) readSnapshot() (snapshot *Snapshot, err error) {
+	if locker, ok := watcher.Source.(Locker); ok {
+		if err = locker.Lock(); err != nil {
+			return nil, err
+		}
+		defer func() {
+			err = errors.Join(err, locker.ReleaseLock())
+		}()
+	}
+
+	info, err := watcher.Source.GetHeader()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewSnapshot(watcher.Source, info)
+}
@@ -0,0 +1,120 @@
+package hwinfoshmem_test
+
+import (
+	"context"
+	"errors"
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/hwinfoshmem"
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/hwinfosim"
+	"testing"
+	"time"
+)
+
+// targetSource reads the latest image written to a target, like a reader of shared memory.
+type targetSource struct {
+	target *hwinfosim.MemoryTarget
+	*hwinfoshmem.Decoder
+}
+
+func (source *targetSource) GetHeader() (*hwinfoshmem.HwinfoHeader, error) {
+	source.Decoder.Bytes = source.target.Bytes()
+	return source.Decoder.GetHeader()
+}
+
+func receiveEvent(t *testing.T, events <-chan hwinfoshmem.WatchEvent) hwinfoshmem.WatchEvent {
+	t.Helper()
+
+	select {
+	case event := <-events:
+		return event
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for event")
+		return hwinfoshmem.WatchEvent{}
+	}
+}
+
+func TestWatcher(t *testing.T) {
+	now := time.Unix(1694966200, 0)
+	target := &hwinfosim.MemoryTarget{}
+	simulator := hwinfosim.NewSimulator(target)
+	simulator.Now = func() time.Time {
+		return now
+	}
+	simulator.AddSensor(&hwinfosim.Sensor{
+		SensorId:   0xF0000300,
+		SensorName: "CPU [#0]: AMD Ryzen 9 7950X",
+		Readings: []*hwinfosim.Reading{{
+			Type:      hwinfoshmem.SENSOR_TYPE_TEMP,
+			Id:        0x1000000,
+			UserLabel: "CPU (Tctl/Tdie)",
+			Unit:      "°C",
+			Generator: hwinfosim.Replay([]float64{40, 50}),
+		}},
+	})
+
+	if err := simulator.Update(); err != nil {
+		t.Fatalf("failed to update: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	watcher := hwinfoshmem.NewWatcher(&targetSource{target: target, Decoder: hwinfoshmem.NewDecoder(nil)})
+	watcher.Interval = time.Millisecond
+	events := watcher.Watch(ctx)
+
+	event := receiveEvent(t, events)
+	if event.Kind != hwinfoshmem.WatchEventSnapshot || event.Snapshot.Readings[0].Value != 40 {
+		t.Fatalf("expected snapshot with value 40, got %v %+v", event.Kind, event.Snapshot)
+	}
+
+	if err := simulator.SetActive(false); err != nil {
+		t.Fatalf("failed to deactivate: %v", err)
+	}
+
+	if event = receiveEvent(t, events); event.Kind != hwinfoshmem.WatchEventInactive {
+		t.Fatalf("expected inactive event, got %v", event.Kind)
+	}
+
+	now = now.Add(2 * time.Second)
+	if err := simulator.SetActive(true); err != nil {
+		t.Fatalf("failed to activate: %v", err)
+	}
+	if err := simulator.Update(); err != nil {
+		t.Fatalf("failed to update: %v", err)
+	}
+
+	if event = receiveEvent(t, events); event.Kind != hwinfoshmem.WatchEventActive {
+		t.Fatalf("expected active event, got %v", event.Kind)
+	}
+
+	// Polls in between updates do not result in events.
+	event = receiveEvent(t, events)
+	if event.Kind != hwinfoshmem.WatchEventSnapshot || !event.Snapshot.LastUpdate.Equal(now) {
+		t.Fatalf("expected snapshot of the last update, got %v %+v", event.Kind, event.Snapshot)
+	}
+
+	if event.Snapshot.Readings[0].Value != 50 {
+		t.Errorf("expected value 50, got %f", event.Snapshot.Readings[0].Value)
+	}
+
+	cancel()
+	for range events {
+	}
+}
+
+func TestWatcherError(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events := hwinfoshmem.NewWatcher(hwinfoshmem.NewDecoder(nil)).Watch(ctx)
+
+	event := receiveEvent(t, events)
+	if event.Kind != hwinfoshmem.WatchEventError || !errors.Is(event.Err, hwinfoshmem.ErrTruncated) {
+		t.Errorf("expected error event wrapping ErrTruncated, got %v %v", event.Kind, event.Err)
+	}
+
+	cancel()
+	if _, ok := <-events; ok {
+		t.Errorf("expected channel to be closed")
+	}
+}
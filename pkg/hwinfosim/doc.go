@@ -0,0 +1,5 @@
+/*
+Package hwinfosim simulates HWiNFO by continuously writing its shared memory layout to a [Target].
+This allows developing and testing code that uses package hwinfoshmem on systems without HWiNFO.
+*/
+package hwinfosim
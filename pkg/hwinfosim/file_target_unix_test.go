@@ -0,0 +1,73 @@
+//go:build unix
+
+package hwinfosim_test
+
+import (
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/hwinfoshmem"
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/hwinfosim"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileTarget(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "HWiNFO_SENS_SM2")
+	producerLocker := hwinfoshmem.NewFileLocker(path)
+	producerLocker.Exclusive = true
+
+	now := time.Unix(1694966200, 0)
+	simulator := newTestSimulator(&hwinfosim.FileTarget{Path: path, Locker: producerLocker}, &now)
+
+	if err := simulator.Update(); err != nil {
+		t.Fatalf("failed to update: %v", err)
+	}
+
+	fileReader := hwinfoshmem.NewFileReader(path)
+	fileReader.Locker = hwinfoshmem.NewFileLocker(path)
+	if err := fileReader.Open(); err != nil {
+		t.Fatalf("failed to open: %v", err)
+	}
+	defer fileReader.Close()
+
+	// Removing a sensor makes the image smaller, the file must not shrink.
+	sizeBefore := fileSize(t, path)
+	simulator.RemoveSensor(0xF0000100, 0)
+	if err := simulator.Update(); err != nil {
+		t.Fatalf("failed to update: %v", err)
+	}
+
+	if size := fileSize(t, path); size != sizeBefore {
+		t.Errorf("expected file size to stay %d, got %d", sizeBefore, size)
+	}
+
+	if err := fileReader.Lock(); err != nil {
+		t.Fatalf("failed to lock: %v", err)
+	}
+	defer fileReader.ReleaseLock()
+
+	info, err := fileReader.GetHeader()
+	if err != nil {
+		t.Fatalf("failed to get header: %v", err)
+	}
+
+	snapshot, err := hwinfoshmem.NewSnapshot(fileReader, info)
+	if err != nil {
+		t.Fatalf("failed to create snapshot: %v", err)
+	}
+
+	if len(snapshot.Sensors) != 1 || snapshot.Sensors[0].SensorName != "Drive 1" {
+		t.Errorf("unexpected sensors %+v", snapshot.Sensors)
+	}
+}
+
+func fileSize(t *testing.T, path string) int64 {
+	t.Helper()
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("failed to stat file: %v", err)
+	}
+
+	return info.Size()
+}
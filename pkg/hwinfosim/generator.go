@@ -0,0 +1,112 @@
+package hwinfosim
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Generator produces the values of a simulated reading.
+type Generator interface {
+	// Value returns the value of the reading at the given time.
+	// It is called once per update of the simulator.
+	Value(now time.Time) float64
+}
+
+// GeneratorFunc allows using a function as a [Generator].
+type GeneratorFunc func(now time.Time) float64
+
+func (f GeneratorFunc) Value(now time.Time) float64 {
+	return f(now)
+}
+
+// Constant returns a [Generator] that always produces the same value.
+func Constant(value float64) Generator {
+	return GeneratorFunc(func(time.Time) float64 {
+		return value
+	})
+}
+
+// Sine returns a [Generator] that produces a sine wave around center with the given amplitude and
+// period.
+func Sine(center float64, amplitude float64, period time.Duration) Generator {
+	return GeneratorFunc(func(now time.Time) float64 {
+		if period <= 0 {
+			return center
+		}
+
+		phase := float64(now.UnixNano()%int64(period)) / float64(period)
+		return center + amplitude*math.Sin(2*math.Pi*phase)
+	})
+}
+
+// RandomWalk returns a [Generator] that starts at start and changes by a random amount between
+// -step and step each update while staying between minimum and maximum.
+// The seed makes the produced values reproducible.
+func RandomWalk(start float64, step float64, minimum float64, maximum float64, seed int64) Generator {
+	random := rand.New(rand.NewSource(seed))
+	value := start
+
+	return GeneratorFunc(func(time.Time) float64 {
+		value += (random.Float64()*2 - 1) * step
+		value = math.Max(minimum, math.Min(maximum, value))
+		return value
+	})
+}
+
+// Replay returns a [Generator] that produces the given values in order, starting over after the
+// last value.
+func Replay(values []float64) Generator {
+	index := 0
+
+	return GeneratorFunc(func(time.Time) float64 {
+		if len(values) == 0 {
+			return 0
+		}
+
+		value := values[index]
+		index = (index + 1) % len(values)
+		return value
+	})
+}
+
+// ReplayFile returns a [Generator] that produces the values in the file, see [Replay].
+// The file contains one value per line. Empty lines and lines starting with # are ignored.
+func ReplayFile(path string) (Generator, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening replay file: %w", err)
+	}
+	defer file.Close()
+
+	values := make([]float64, 0)
+	scanner := bufio.NewScanner(file)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		value, err := strconv.ParseFloat(line, 64)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing line %d of replay file: %w", lineNumber, err)
+		}
+		values = append(values, value)
+	}
+
+	if err = scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading replay file: %w", err)
+	}
+
+	if len(values) == 0 {
+		return nil, errors.New("replay file contains no values")
+	}
+
+	return Replay(values), nil
+}
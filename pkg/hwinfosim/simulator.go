@@ -0,0 +1,245 @@
+package hwinfosim
+
+import (
+	"context"
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/hwinfoshmem"
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/util/bytesutil"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Sensor is a simulated sensor, see [hwinfoshmem.HwinfoSensor].
+type Sensor struct {
+	SensorId           uint32
+	SensorInstance     uint32
+	SensorNameOriginal string
+	SensorName         string
+	Readings           []*Reading
+}
+
+// Reading is a simulated reading, see [hwinfoshmem.HwinfoReading].
+// The minimum, maximum, and average are maintained by the simulator starting from when the sensor
+// was added.
+type Reading struct {
+	Type          hwinfoshmem.ReadingType
+	Id            uint32
+	OriginalLabel string
+	UserLabel     string
+	Unit          string
+
+	// Generator produces the values of the reading.
+	Generator Generator
+
+	value   float64
+	minimum float64
+	maximum float64
+	sum     float64
+	count   uint64
+}
+
+// Simulator writes HWiNFO's shared memory layout, containing the configured sensors, to the
+// target every polling period.
+// Sensors can be added and removed while running to simulate hot-plugging.
+//
+// Simulator has an initializer function, [NewSimulator].
+type Simulator struct {
+	// Target receives the images.
+	Target Target
+
+	// PollingPeriod is the time between updates.
+	PollingPeriod time.Duration
+
+	// Version of the layout to write, see [hwinfoshmem.LayoutInfo].
+	Version uint32
+
+	// Revision of the layout to write, see [hwinfoshmem.LayoutInfo].
+	Revision uint32
+
+	// Codepage used to encode the ASCII strings.
+	Codepage bytesutil.Codepage
+
+	// Now returns the current time. Can be replaced to control time in tests.
+	Now func() time.Time
+
+	mutex      sync.Mutex
+	sensors    []*Sensor
+	inactive   bool
+	lastUpdate time.Time
+}
+
+// NewSimulator returns a Simulator that writes the newest layout to the target every 2 seconds.
+func NewSimulator(target Target) *Simulator {
+	return &Simulator{
+		Target:        target,
+		PollingPeriod: 2 * time.Second,
+		Version:       2,
+		Revision:      1,
+		Codepage:      bytesutil.Codepage1252,
+		Now:           time.Now,
+	}
+}
+
+// AddSensor adds the sensor after the existing sensors.
+// The sensor will be part of the image written by the next update.
+func (simulator *Simulator) AddSensor(sensor *Sensor) {
+	simulator.mutex.Lock()
+	defer simulator.mutex.Unlock()
+
+	simulator.sensors = append(simulator.sensors, sensor)
+}
+
+// RemoveSensor removes the sensor with the given id and instance.
+// The sensors after it move up which changes their index, like in HWiNFO.
+// Reports whether the sensor existed.
+func (simulator *Simulator) RemoveSensor(sensorId uint32, sensorInstance uint32) bool {
+	simulator.mutex.Lock()
+	defer simulator.mutex.Unlock()
+
+	length := len(simulator.sensors)
+	simulator.sensors = slices.DeleteFunc(simulator.sensors, func(sensor *Sensor) bool {
+		return sensor.SensorId == sensorId && sensor.SensorInstance == sensorInstance
+	})
+
+	return len(simulator.sensors) != length
+}
+
+// SetActive changes the status to "HWiS" when active is true and to "DAED" otherwise and writes
+// the image.
+// While inactive, updates do not change the values or the last update time, like HWiNFO after
+// its shared memory time limit expired.
+func (simulator *Simulator) SetActive(active bool) error {
+	simulator.mutex.Lock()
+	defer simulator.mutex.Unlock()
+
+	simulator.inactive = !active
+
+	return simulator.write()
+}
+
+// Update produces new values for all readings and writes the image.
+func (simulator *Simulator) Update() error {
+	simulator.mutex.Lock()
+	defer simulator.mutex.Unlock()
+
+	if !simulator.inactive {
+		now := simulator.Now()
+		simulator.lastUpdate = now
+
+		for _, sensor := range simulator.sensors {
+			for _, reading := range sensor.Readings {
+				reading.update(now)
+			}
+		}
+	}
+
+	return simulator.write()
+}
+
+// Run calls Update every polling period until the context is done.
+// When the context is done, the status is set to inactive, like HWiNFO does when it exits.
+// Returns nil when stopped by the context, otherwise the error of the failed update.
+func (simulator *Simulator) Run(ctx context.Context) error {
+	if err := simulator.Update(); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(simulator.PollingPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return simulator.SetActive(false)
+		case <-ticker.C:
+			if err := simulator.Update(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Snapshot returns the current state of the simulator.
+func (simulator *Simulator) Snapshot() *hwinfoshmem.Snapshot {
+	simulator.mutex.Lock()
+	defer simulator.mutex.Unlock()
+
+	return simulator.snapshot()
+}
+
+func (simulator *Simulator) snapshot() *hwinfoshmem.Snapshot {
+	snapshot := &hwinfoshmem.Snapshot{
+		Active:        !simulator.inactive,
+		Version:       simulator.Version,
+		Revision:      simulator.Revision,
+		LastUpdate:    simulator.lastUpdate,
+		PollingPeriod: simulator.PollingPeriod,
+		Sensors:       make([]*hwinfoshmem.Sensor, len(simulator.sensors)),
+		Readings:      make([]*hwinfoshmem.Reading, 0),
+	}
+
+	for i, simulatedSensor := range simulator.sensors {
+		sensor := &hwinfoshmem.Sensor{
+			Index:              uint32(i),
+			SensorId:           simulatedSensor.SensorId,
+			SensorInstance:     simulatedSensor.SensorInstance,
+			SensorNameOriginal: simulatedSensor.SensorNameOriginal,
+			SensorName:         simulatedSensor.SensorName,
+			Readings:           make([]*hwinfoshmem.Reading, len(simulatedSensor.Readings)),
+		}
+		snapshot.Sensors[i] = sensor
+
+		for j, simulatedReading := range simulatedSensor.Readings {
+			reading := &hwinfoshmem.Reading{
+				Sensor:        sensor,
+				Type:          simulatedReading.Type,
+				SensorIndex:   uint32(i),
+				Id:            simulatedReading.Id,
+				OriginalLabel: simulatedReading.OriginalLabel,
+				UserLabel:     simulatedReading.UserLabel,
+				Unit:          simulatedReading.Unit,
+				Value:         simulatedReading.value,
+				ValueMin:      simulatedReading.minimum,
+				ValueMax:      simulatedReading.maximum,
+				ValueAvg:      simulatedReading.average(),
+			}
+			sensor.Readings[j] = reading
+			snapshot.Readings = append(snapshot.Readings, reading)
+		}
+	}
+
+	return snapshot
+}
+
+func (simulator *Simulator) write() error {
+	image, err := hwinfoshmem.EncodeSnapshot(simulator.snapshot(), simulator.Codepage)
+	if err != nil {
+		return err
+	}
+
+	return simulator.Target.Write(image)
+}
+
+func (reading *Reading) update(now time.Time) {
+	if reading.Generator == nil {
+		return
+	}
+
+	reading.value = reading.Generator.Value(now)
+	if reading.count == 0 || reading.value < reading.minimum {
+		reading.minimum = reading.value
+	}
+	if reading.count == 0 || reading.value > reading.maximum {
+		reading.maximum = reading.value
+	}
+	reading.sum += reading.value
+	reading.count++
+}
+
+func (reading *Reading) average() float64 {
+	if reading.count == 0 {
+		return 0
+	}
+
+	return reading.sum / float64(reading.count)
+}
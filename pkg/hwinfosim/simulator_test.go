@@ -0,0 +1,176 @@
+package hwinfosim_test
+
+import (
+	"fmt"
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/hwinfoshmem"
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/hwinfosim"
+	"testing"
+	"time"
+)
+
+func Example() {
+	target := &hwinfosim.MemoryTarget{}
+	simulator := hwinfosim.NewSimulator(target)
+	simulator.AddSensor(&hwinfosim.Sensor{
+		SensorId:           0xF0000300,
+		SensorNameOriginal: "CPU [#0]: AMD Ryzen 9 7950X",
+		SensorName:         "CPU [#0]: AMD Ryzen 9 7950X",
+		Readings: []*hwinfosim.Reading{{
+			Type:          hwinfoshmem.SENSOR_TYPE_TEMP,
+			Id:            0x1000000,
+			OriginalLabel: "CPU (Tctl/Tdie)",
+			UserLabel:     "CPU (Tctl/Tdie)",
+			Unit:          "°C",
+			Generator:     hwinfosim.Replay([]float64{40, 50, 60}),
+		}},
+	})
+
+	// Use simulator.Run(ctx) to update every polling period.
+	for i := 0; i < 3; i++ {
+		if err := simulator.Update(); err != nil {
+			fmt.Printf("Failed to update: %s\n", err)
+			return
+		}
+	}
+
+	bytesReader := target.BytesReader()
+	hwInfo, err := bytesReader.GetHeader()
+	if err != nil {
+		fmt.Printf("Failed to get header: %s\n", err)
+		return
+	}
+
+	readings, err := bytesReader.GetReadings(hwInfo)
+	if err != nil {
+		fmt.Printf("Error getting readings %v\n", err)
+		return
+	}
+
+	for _, reading := range readings {
+		fmt.Printf(
+			"%s: %.1f %s (min %.1f, max %.1f, avg %.1f)\n",
+			reading.UserLabel,
+			reading.Value.ToFloat64(),
+			reading.Unit,
+			reading.ValueMin.ToFloat64(),
+			reading.ValueMax.ToFloat64(),
+			reading.ValueAvg.ToFloat64(),
+		)
+	}
+
+	// Output:
+	// CPU (Tctl/Tdie): 60.0 °C (min 40.0, max 60.0, avg 50.0)
+}
+
+func newTestSimulator(target hwinfosim.Target, now *time.Time) *hwinfosim.Simulator {
+	simulator := hwinfosim.NewSimulator(target)
+	simulator.Now = func() time.Time {
+		return *now
+	}
+
+	for instance := uint32(0); instance < 2; instance++ {
+		simulator.AddSensor(&hwinfosim.Sensor{
+			SensorId:       0xF0000100,
+			SensorInstance: instance,
+			SensorName:     fmt.Sprintf("Drive %d", instance),
+			Readings: []*hwinfosim.Reading{{
+				Type:      hwinfoshmem.SENSOR_TYPE_TEMP,
+				Id:        1,
+				UserLabel: "Drive Temperature",
+				Unit:      "°C",
+				Generator: hwinfosim.Constant(30 + float64(instance)),
+			}},
+		})
+	}
+
+	return simulator
+}
+
+func readSnapshot(t *testing.T, target *hwinfosim.MemoryTarget) *hwinfoshmem.Snapshot {
+	t.Helper()
+
+	bytesReader := target.BytesReader()
+	info, err := bytesReader.GetHeader()
+	if err != nil {
+		t.Fatalf("failed to get header: %v", err)
+	}
+
+	snapshot, err := hwinfoshmem.NewSnapshot(bytesReader, info)
+	if err != nil {
+		t.Fatalf("failed to create snapshot: %v", err)
+	}
+
+	return snapshot
+}
+
+func TestSimulator(t *testing.T) {
+	now := time.Unix(1694966200, 0)
+	target := &hwinfosim.MemoryTarget{}
+	simulator := newTestSimulator(target, &now)
+
+	if err := simulator.Update(); err != nil {
+		t.Fatalf("failed to update: %v", err)
+	}
+
+	snapshot := readSnapshot(t, target)
+	if !snapshot.Active || !snapshot.LastUpdate.Equal(now) || snapshot.PollingPeriod != 2*time.Second {
+		t.Errorf("unexpected header %+v", snapshot)
+	}
+
+	if len(snapshot.Readings) != 2 || snapshot.Readings[1].Value != 31 {
+		t.Fatalf("unexpected readings %+v", snapshot.Readings)
+	}
+
+	// Hot-unplug the first drive, the second drive moves to index 0.
+	if !simulator.RemoveSensor(0xF0000100, 0) {
+		t.Errorf("expected sensor to be removed")
+	}
+
+	now = now.Add(2 * time.Second)
+	if err := simulator.Update(); err != nil {
+		t.Fatalf("failed to update: %v", err)
+	}
+
+	snapshot = readSnapshot(t, target)
+	if len(snapshot.Sensors) != 1 || snapshot.Sensors[0].SensorInstance != 1 {
+		t.Fatalf("unexpected sensors %+v", snapshot.Sensors)
+	}
+
+	if snapshot.Readings[0].SensorIndex != 0 || snapshot.Readings[0].Value != 31 {
+		t.Errorf("unexpected reading %+v", snapshot.Readings[0])
+	}
+
+	// HWiNFO stops updating after becoming inactive.
+	if err := simulator.SetActive(false); err != nil {
+		t.Fatalf("failed to deactivate: %v", err)
+	}
+
+	lastUpdate := now
+	now = now.Add(2 * time.Second)
+	if err := simulator.Update(); err != nil {
+		t.Fatalf("failed to update: %v", err)
+	}
+
+	snapshot = readSnapshot(t, target)
+	if snapshot.Active || snapshot.Status != "DAED" || !snapshot.LastUpdate.Equal(lastUpdate) {
+		t.Errorf("unexpected header of inactive simulator %+v", snapshot)
+	}
+}
+
+func TestGenerators(t *testing.T) {
+	start := time.Unix(0, 0)
+
+	sine := hwinfosim.Sine(50, 10, 4*time.Second)
+	for offset, expected := range map[time.Duration]float64{0: 50, time.Second: 60, 3 * time.Second: 40} {
+		if value := sine.Value(start.Add(offset)); value < expected-1e-9 || value > expected+1e-9 {
+			t.Errorf("sine at %v: expected %f, got %f", offset, expected, value)
+		}
+	}
+
+	walk := hwinfosim.RandomWalk(50, 5, 45, 55, 1)
+	for i := 0; i < 100; i++ {
+		if value := walk.Value(start); value < 45 || value > 55 {
+			t.Fatalf("random walk left its bounds: %f", value)
+		}
+	}
+}
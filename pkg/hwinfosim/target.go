@@ -0,0 +1,95 @@
+package hwinfosim
+
+import (
+	"fmt"
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/hwinfoshmem"
+	"os"
+	"sync"
+)
+
+// Target receives the images written by the [Simulator].
+type Target interface {
+	// Write replaces the current image with the given image.
+	Write(image []byte) error
+}
+
+// MemoryTarget keeps the latest image in memory.
+type MemoryTarget struct {
+	mutex sync.RWMutex
+	image []byte
+}
+
+func (target *MemoryTarget) Write(image []byte) error {
+	target.mutex.Lock()
+	defer target.mutex.Unlock()
+
+	target.image = append(target.image[:0], image...)
+
+	return nil
+}
+
+// Bytes returns a copy of the latest image.
+func (target *MemoryTarget) Bytes() []byte {
+	target.mutex.RLock()
+	defer target.mutex.RUnlock()
+
+	image := make([]byte, len(target.image))
+	copy(image, target.image)
+
+	return image
+}
+
+// BytesReader returns a [hwinfoshmem.BytesReader] for a copy of the latest image.
+func (target *MemoryTarget) BytesReader() *hwinfoshmem.BytesReader {
+	return hwinfoshmem.NewBytesReader(target.Bytes())
+}
+
+// FileTarget writes the images to a file in place, like HWiNFO does with its shared memory.
+// The file can be read using hwinfoshmem.FileReader.
+//
+// The file never shrinks, when an image is smaller than the file, the rest of the file is zeroed.
+// This prevents readers that map the file from reading past its end.
+type FileTarget struct {
+	// Path of the file to write to. The file is created when it does not exist.
+	Path string
+
+	// Locker that is held while writing, e.g. an exclusive hwinfoshmem.FileLocker.
+	// When nil, no locking is performed.
+	Locker hwinfoshmem.Locker
+}
+
+func (target *FileTarget) Write(image []byte) (err error) {
+	file, err := os.OpenFile(target.Path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return fmt.Errorf("error opening target file: %w", err)
+	}
+	defer file.Close()
+
+	if target.Locker != nil {
+		if err = target.Locker.Lock(); err != nil {
+			return err
+		}
+		defer func() {
+			if releaseErr := target.Locker.ReleaseLock(); err == nil {
+				err = releaseErr
+			}
+		}()
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("error getting size of target file: %w", err)
+	}
+
+	if info.Size() > int64(len(image)) {
+		padded := make([]byte, info.Size())
+		copy(padded, image)
+		image = padded
+	}
+
+	if _, err = file.WriteAt(image, 0); err != nil {
+		return fmt.Errorf("error writing target file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,217 @@
+package recording
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+)
+
+// bitWriter appends bits to a byte slice, most significant bit first.
+type bitWriter struct {
+	bytes []byte
+
+	// free is the amount of unused bits in the last byte.
+	free uint8
+}
+
+func (writer *bitWriter) reset() {
+	writer.bytes = writer.bytes[:0]
+	writer.free = 0
+}
+
+func (writer *bitWriter) writeBit(bit bool) {
+	if writer.free == 0 {
+		writer.bytes = append(writer.bytes, 0)
+		writer.free = 8
+	}
+
+	writer.free--
+	if bit {
+		writer.bytes[len(writer.bytes)-1] |= 1 << writer.free
+	}
+}
+
+// writeBits writes the lowest count bits of value.
+func (writer *bitWriter) writeBits(value uint64, count int) {
+	for i := count - 1; i >= 0; i-- {
+		writer.writeBit(value&(1<<i) != 0)
+	}
+}
+
+// bitReader reads the bits written by bitWriter. Reading past the end sets err and returns zeros.
+type bitReader struct {
+	bytes    []byte
+	position uint64
+	err      error
+}
+
+func (reader *bitReader) readBit() bool {
+	if reader.position >= uint64(len(reader.bytes))*8 {
+		if reader.err == nil {
+			reader.err = fmt.Errorf("%w: frame ends unexpectedly", ErrInvalidRecording)
+		}
+		return false
+	}
+
+	bit := reader.bytes[reader.position/8]&(0x80>>(reader.position%8)) != 0
+	reader.position++
+
+	return bit
+}
+
+func (reader *bitReader) readBits(count int) uint64 {
+	var value uint64
+	for i := 0; i < count; i++ {
+		value <<= 1
+		if reader.readBit() {
+			value |= 1
+		}
+	}
+
+	return value
+}
+
+// timeColumn compresses timestamps by storing the difference between consecutive deltas, which is
+// zero when the polling period is constant.
+type timeColumn struct {
+	previous int64
+	delta    int64
+	started  bool
+}
+
+// timeBuckets are the ranges of delta-of-deltas that are stored using the given amount of bits
+// after a prefix of as many ones as the position of the bucket followed by a zero.
+var timeBuckets = []struct {
+	bits   int
+	offset int64
+}{
+	{bits: 7, offset: 63},
+	{bits: 9, offset: 255},
+	{bits: 12, offset: 2047},
+}
+
+func (column *timeColumn) write(writer *bitWriter, timestamp int64) {
+	if !column.started {
+		writer.writeBits(uint64(timestamp), 64)
+		column.previous = timestamp
+		column.started = true
+		return
+	}
+
+	delta := timestamp - column.previous
+	deltaOfDelta := delta - column.delta
+	column.previous = timestamp
+	column.delta = delta
+
+	if deltaOfDelta == 0 {
+		writer.writeBit(false)
+		return
+	}
+
+	for _, bucket := range timeBuckets {
+		writer.writeBit(true)
+		if deltaOfDelta >= -bucket.offset && deltaOfDelta <= bucket.offset+1 {
+			writer.writeBit(false)
+			writer.writeBits(uint64(deltaOfDelta+bucket.offset), bucket.bits)
+			return
+		}
+	}
+
+	writer.writeBit(true)
+	writer.writeBits(uint64(deltaOfDelta), 64)
+}
+
+func (column *timeColumn) read(reader *bitReader) int64 {
+	if !column.started {
+		column.previous = int64(reader.readBits(64))
+		column.started = true
+		return column.previous
+	}
+
+	var deltaOfDelta int64
+	if reader.readBit() {
+		found := false
+		for _, bucket := range timeBuckets {
+			if !reader.readBit() {
+				deltaOfDelta = int64(reader.readBits(bucket.bits)) - bucket.offset
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			deltaOfDelta = int64(reader.readBits(64))
+		}
+	}
+
+	column.delta += deltaOfDelta
+	column.previous += column.delta
+
+	return column.previous
+}
+
+// floatColumn compresses floats by storing the XOR with the previous value, see the Gorilla paper
+// by Facebook. Values that do not change take a single bit.
+type floatColumn struct {
+	previous uint64
+	leading  int
+	trailing int
+	window   bool
+}
+
+func (column *floatColumn) write(writer *bitWriter, value float64) {
+	current := math.Float64bits(value)
+	xor := current ^ column.previous
+	column.previous = current
+
+	if xor == 0 {
+		writer.writeBit(false)
+		return
+	}
+	writer.writeBit(true)
+
+	leading := min(bits.LeadingZeros64(xor), 31)
+	trailing := bits.TrailingZeros64(xor)
+
+	if column.window && leading >= column.leading && trailing >= column.trailing {
+		writer.writeBit(false)
+		writer.writeBits(xor>>column.trailing, 64-column.leading-column.trailing)
+		return
+	}
+
+	meaningful := 64 - leading - trailing
+	writer.writeBit(true)
+	writer.writeBits(uint64(leading), 5)
+	writer.writeBits(uint64(meaningful-1), 6)
+	writer.writeBits(xor>>trailing, meaningful)
+
+	column.leading = leading
+	column.trailing = trailing
+	column.window = true
+}
+
+func (column *floatColumn) read(reader *bitReader) float64 {
+	if reader.readBit() {
+		if reader.readBit() {
+			column.leading = int(reader.readBits(5))
+			column.trailing = 64 - column.leading - int(reader.readBits(6)) - 1
+			column.window = true
+		} else if !column.window {
+			if reader.err == nil {
+				reader.err = fmt.Errorf("%w: value refers to a missing window", ErrInvalidRecording)
+			}
+			return 0
+		}
+
+		if column.trailing < 0 {
+			if reader.err == nil {
+				reader.err = fmt.Errorf("%w: invalid value window", ErrInvalidRecording)
+			}
+			return 0
+		}
+
+		column.previous ^= reader.readBits(64-column.leading-column.trailing) << column.trailing
+	}
+
+	return math.Float64frombits(column.previous)
+}
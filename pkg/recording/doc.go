@@ -0,0 +1,20 @@
+/*
+Package recording stores a series of [hwinfoshmem.Snapshot] compactly, e.g. to keep days of history
+of several machines for analyzing thermal issues afterward.
+
+A recording starts with a header containing the host name, followed by blocks of two kinds:
+  - a topology block contains the sensors and readings of a snapshot without the values
+  - a frame block contains the time and the Value, ValueMin, ValueMax, and ValueAvg of each
+    reading of a single snapshot, in the order of the preceding topology
+
+A topology block is only written when the sensors or readings change, e.g. when a sensor is added
+or the user renames a reading. The values are compressed as described in the [Gorilla paper]: times
+are stored as the difference between consecutive intervals, and values as the XOR with the previous
+value of the same reading. A value that did not change takes a single bit.
+
+Each block ends with a checksum. A recording that was cut short can be read up to the last complete
+block.
+
+[Gorilla paper]: https://www.vldb.org/pvldb/vol8/p1816-teller.pdf
+*/
+package recording
@@ -0,0 +1,252 @@
+package recording
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/hwinfoshmem"
+	"hash/crc32"
+	"time"
+)
+
+// ErrInvalidRecording is returned when reading a recording that is corrupt or of an unsupported
+// format version.
+var ErrInvalidRecording = errors.New("invalid recording")
+
+// FormatVersion is the format version written by [Writer].
+const FormatVersion = 1
+
+// magic starts every recording.
+var magic = [8]byte{'H', 'W', 'i', 'R', 'E', 'C', 0x1a, 0}
+
+// blockKind identifies the contents of a block.
+type blockKind byte
+
+const (
+	// blockTopology contains the sensors and readings without their values. It resets the
+	// compression state.
+	blockTopology blockKind = 1
+
+	// blockFrame contains the time and the values of all readings of a single poll.
+	blockFrame blockKind = 2
+)
+
+// maxBlockSize limits the memory used when reading a corrupt block length.
+const maxBlockSize = 64 << 20
+
+// appendBlock appends a block consisting of the kind, the size of the payload as uvarint, the
+// payload, and the CRC-32 of the kind and payload.
+func appendBlock(data []byte, kind blockKind, payload []byte) []byte {
+	start := len(data)
+	data = append(data, byte(kind))
+	data = binary.AppendUvarint(data, uint64(len(payload)))
+	kindAndSizeEnd := len(data)
+	data = append(data, payload...)
+
+	checksum := crc32.Update(crc32.ChecksumIEEE(data[start:start+1]), crc32.IEEETable, data[kindAndSizeEnd:])
+	return binary.LittleEndian.AppendUint32(data, checksum)
+}
+
+func appendString(data []byte, value string) []byte {
+	data = binary.AppendUvarint(data, uint64(len(value)))
+	return append(data, value...)
+}
+
+// appendTopology encodes everything about the snapshot except for the time and the values.
+func appendTopology(data []byte, snapshot *hwinfoshmem.Snapshot) []byte {
+	var flags byte
+	if snapshot.Active {
+		flags |= 1
+	}
+	if snapshot.Layout.HasPollingPeriod {
+		flags |= 2
+	}
+	if snapshot.Layout.HasUtf8Strings {
+		flags |= 4
+	}
+
+	data = append(data, flags)
+	data = appendString(data, snapshot.Status)
+	data = binary.AppendUvarint(data, uint64(snapshot.Version))
+	data = binary.AppendUvarint(data, uint64(snapshot.Revision))
+	data = binary.AppendUvarint(data, uint64(snapshot.PollingPeriod.Milliseconds()))
+	data = binary.AppendUvarint(data, uint64(snapshot.Layout.Version))
+	data = binary.AppendUvarint(data, uint64(snapshot.Layout.Revision))
+	data = binary.AppendUvarint(data, uint64(snapshot.Layout.HeaderSize))
+	data = binary.AppendUvarint(data, uint64(snapshot.Layout.SensorSize))
+	data = binary.AppendUvarint(data, uint64(snapshot.Layout.ReadingSize))
+
+	data = binary.AppendUvarint(data, uint64(len(snapshot.Sensors)))
+	for _, sensor := range snapshot.Sensors {
+		data = binary.AppendUvarint(data, uint64(sensor.SensorId))
+		data = binary.AppendUvarint(data, uint64(sensor.SensorInstance))
+		data = appendString(data, sensor.SensorNameOriginal)
+		data = appendString(data, sensor.SensorName)
+	}
+
+	data = binary.AppendUvarint(data, uint64(len(snapshot.Readings)))
+	for _, reading := range snapshot.Readings {
+		data = binary.AppendUvarint(data, uint64(reading.Type))
+		data = binary.AppendUvarint(data, uint64(reading.SensorIndex))
+		data = binary.AppendUvarint(data, uint64(reading.Id))
+		data = appendString(data, reading.OriginalLabel)
+		data = appendString(data, reading.UserLabel)
+		data = appendString(data, reading.Unit)
+	}
+
+	return data
+}
+
+// payloadDecoder reads the values appended by appendTopology. Reading past the end sets err and
+// returns zero values.
+type payloadDecoder struct {
+	data []byte
+	err  error
+}
+
+func (decoder *payloadDecoder) fail(format string, args ...any) {
+	if decoder.err == nil {
+		decoder.err = fmt.Errorf("%w: %s", ErrInvalidRecording, fmt.Sprintf(format, args...))
+	}
+	decoder.data = nil
+}
+
+func (decoder *payloadDecoder) byte() byte {
+	if len(decoder.data) == 0 {
+		decoder.fail("topology ends unexpectedly")
+		return 0
+	}
+
+	value := decoder.data[0]
+	decoder.data = decoder.data[1:]
+
+	return value
+}
+
+func (decoder *payloadDecoder) uvarint() uint64 {
+	value, size := binary.Uvarint(decoder.data)
+	if size <= 0 {
+		decoder.fail("invalid number in topology")
+		return 0
+	}
+
+	decoder.data = decoder.data[size:]
+
+	return value
+}
+
+func (decoder *payloadDecoder) uint32() uint32 {
+	value := decoder.uvarint()
+	if value > 0xffffffff {
+		decoder.fail("number %d in topology exceeds 32 bits", value)
+		return 0
+	}
+
+	return uint32(value)
+}
+
+// count reads the amount of elements that follow, each of which takes at least minimumSize bytes.
+func (decoder *payloadDecoder) count(minimumSize int) int {
+	value := decoder.uvarint()
+	if value > uint64(len(decoder.data)/minimumSize) {
+		decoder.fail("amount of %d exceeds the topology", value)
+		return 0
+	}
+
+	return int(value)
+}
+
+func (decoder *payloadDecoder) string() string {
+	size := decoder.uvarint()
+	if size > uint64(len(decoder.data)) {
+		decoder.fail("string of %d bytes exceeds the topology", size)
+		return ""
+	}
+
+	value := string(decoder.data[:size])
+	decoder.data = decoder.data[size:]
+
+	return value
+}
+
+// decodeTopology decodes a snapshot without time and values from a topology block.
+func decodeTopology(payload []byte) (*hwinfoshmem.Snapshot, error) {
+	decoder := &payloadDecoder{data: payload}
+
+	flags := decoder.byte()
+	snapshot := &hwinfoshmem.Snapshot{
+		Active:        flags&1 != 0,
+		Status:        decoder.string(),
+		Version:       decoder.uint32(),
+		Revision:      decoder.uint32(),
+		PollingPeriod: time.Duration(decoder.uint32()) * time.Millisecond,
+	}
+	snapshot.Layout = hwinfoshmem.LayoutInfo{
+		Version:          decoder.uint32(),
+		Revision:         decoder.uint32(),
+		HeaderSize:       decoder.uint32(),
+		SensorSize:       decoder.uint32(),
+		ReadingSize:      decoder.uint32(),
+		HasPollingPeriod: flags&2 != 0,
+		HasUtf8Strings:   flags&4 != 0,
+	}
+
+	snapshot.Sensors = make([]*hwinfoshmem.Sensor, decoder.count(4))
+	for i := range snapshot.Sensors {
+		snapshot.Sensors[i] = &hwinfoshmem.Sensor{
+			Index:              uint32(i),
+			SensorId:           decoder.uint32(),
+			SensorInstance:     decoder.uint32(),
+			SensorNameOriginal: decoder.string(),
+			SensorName:         decoder.string(),
+			Readings:           make([]*hwinfoshmem.Reading, 0),
+		}
+	}
+
+	snapshot.Readings = make([]*hwinfoshmem.Reading, decoder.count(6))
+	for i := range snapshot.Readings {
+		snapshot.Readings[i] = &hwinfoshmem.Reading{
+			Type:          hwinfoshmem.ReadingType(decoder.uint32()),
+			SensorIndex:   decoder.uint32(),
+			Id:            decoder.uint32(),
+			OriginalLabel: decoder.string(),
+			UserLabel:     decoder.string(),
+			Unit:          decoder.string(),
+		}
+	}
+
+	if decoder.err != nil {
+		return nil, decoder.err
+	}
+
+	if len(decoder.data) != 0 {
+		return nil, fmt.Errorf("%w: %d unexpected bytes after topology", ErrInvalidRecording, len(decoder.data))
+	}
+
+	return snapshot, nil
+}
+
+// cloneTopology copies the sensors and readings of a decoded topology so that each snapshot
+// returned by [Reader] can be modified independently.
+func cloneTopology(topology *hwinfoshmem.Snapshot) *hwinfoshmem.Snapshot {
+	snapshot := *topology
+	snapshot.Sensors = make([]*hwinfoshmem.Sensor, len(topology.Sensors))
+	snapshot.Readings = make([]*hwinfoshmem.Reading, len(topology.Readings))
+
+	for i, sensor := range topology.Sensors {
+		sensorCopy := *sensor
+		sensorCopy.Readings = make([]*hwinfoshmem.Reading, 0)
+		snapshot.Sensors[i] = &sensorCopy
+	}
+
+	for i, reading := range topology.Readings {
+		readingCopy := *reading
+		if int(readingCopy.SensorIndex) < len(snapshot.Sensors) {
+			readingCopy.Sensor = snapshot.Sensors[readingCopy.SensorIndex]
+			readingCopy.Sensor.Readings = append(readingCopy.Sensor.Readings, &readingCopy)
+		}
+		snapshot.Readings[i] = &readingCopy
+	}
+
+	return &snapshot
+}
@@ -0,0 +1,168 @@
+package recording
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/hwinfoshmem"
+	"hash/crc32"
+	"io"
+	"time"
+)
+
+// Reader reads the snapshots of a recording written by [Writer].
+//
+// Reader has an initializer function, [NewReader].
+type Reader struct {
+	// The format version of the recording, see [FormatVersion].
+	FormatVersion uint64
+
+	// The name of the machine the snapshots originate from.
+	Hostname string
+
+	reader   *bufio.Reader
+	topology *hwinfoshmem.Snapshot
+	time     timeColumn
+	columns  []floatColumn
+	payload  []byte
+}
+
+// NewReader reads the header of the recording.
+func NewReader(reader io.Reader) (*Reader, error) {
+	recordingReader := &Reader{
+		reader: bufio.NewReader(reader),
+	}
+
+	var header [len(magic)]byte
+	if _, err := io.ReadFull(recordingReader.reader, header[:]); err != nil {
+		return nil, fmt.Errorf("error reading recording header: %w", err)
+	}
+
+	if header != magic {
+		return nil, fmt.Errorf("%w: missing magic bytes", ErrInvalidRecording)
+	}
+
+	formatVersion, err := binary.ReadUvarint(recordingReader.reader)
+	if err != nil {
+		return nil, fmt.Errorf("error reading recording header: %w", err)
+	}
+
+	if formatVersion == 0 || formatVersion > FormatVersion {
+		return nil, fmt.Errorf("%w: unsupported format version %d", ErrInvalidRecording, formatVersion)
+	}
+	recordingReader.FormatVersion = formatVersion
+
+	hostnameSize, err := binary.ReadUvarint(recordingReader.reader)
+	if err != nil {
+		return nil, fmt.Errorf("error reading recording header: %w", err)
+	}
+
+	if hostnameSize > maxBlockSize {
+		return nil, fmt.Errorf("%w: host name of %d bytes", ErrInvalidRecording, hostnameSize)
+	}
+
+	hostname := make([]byte, hostnameSize)
+	if _, err = io.ReadFull(recordingReader.reader, hostname); err != nil {
+		return nil, fmt.Errorf("error reading recording header: %w", noEOF(err))
+	}
+	recordingReader.Hostname = string(hostname)
+
+	return recordingReader, nil
+}
+
+// Read returns the next snapshot.
+// Returns [io.EOF] at the end of the recording and [io.ErrUnexpectedEOF] when the recording ends
+// in the middle of a snapshot, e.g. because the writing process crashed.
+func (reader *Reader) Read() (*hwinfoshmem.Snapshot, error) {
+	for {
+		kind, err := reader.readBlock()
+		if err != nil {
+			return nil, err
+		}
+
+		switch kind {
+		case blockTopology:
+			topology, err := decodeTopology(reader.payload)
+			if err != nil {
+				return nil, err
+			}
+
+			reader.topology = topology
+			reader.time = timeColumn{}
+			reader.columns = make([]floatColumn, 4*len(topology.Readings))
+		case blockFrame:
+			if reader.topology == nil {
+				return nil, fmt.Errorf("%w: values before topology", ErrInvalidRecording)
+			}
+
+			return reader.decodeFrame()
+		default:
+			// Unknown blocks are skipped so that later format versions can add blocks that older
+			// readers do not need to understand.
+		}
+	}
+}
+
+// readBlock reads the next block into payload and verifies its checksum.
+func (reader *Reader) readBlock() (blockKind, error) {
+	kind, err := reader.reader.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+
+	size, err := binary.ReadUvarint(reader.reader)
+	if err != nil {
+		return 0, noEOF(err)
+	}
+
+	if size > maxBlockSize {
+		return 0, fmt.Errorf("%w: block of %d bytes exceeds the maximum of %d", ErrInvalidRecording, size, maxBlockSize)
+	}
+
+	if uint64(cap(reader.payload)) < size+4 {
+		reader.payload = make([]byte, size+4)
+	}
+	reader.payload = reader.payload[:size+4]
+
+	if _, err = io.ReadFull(reader.reader, reader.payload); err != nil {
+		return 0, noEOF(err)
+	}
+
+	checksum := binary.LittleEndian.Uint32(reader.payload[size:])
+	reader.payload = reader.payload[:size]
+
+	if checksum != crc32.Update(crc32.ChecksumIEEE([]byte{kind}), crc32.IEEETable, reader.payload) {
+		return 0, fmt.Errorf("%w: checksum mismatch", ErrInvalidRecording)
+	}
+
+	return blockKind(kind), nil
+}
+
+func (reader *Reader) decodeFrame() (*hwinfoshmem.Snapshot, error) {
+	bits := &bitReader{bytes: reader.payload}
+	snapshot := cloneTopology(reader.topology)
+
+	snapshot.LastUpdate = time.UnixMilli(reader.time.read(bits))
+	for i, reading := range snapshot.Readings {
+		reading.Value = reader.columns[4*i].read(bits)
+		reading.ValueMin = reader.columns[4*i+1].read(bits)
+		reading.ValueMax = reader.columns[4*i+2].read(bits)
+		reading.ValueAvg = reader.columns[4*i+3].read(bits)
+	}
+
+	if bits.err != nil {
+		return nil, bits.err
+	}
+
+	return snapshot, nil
+}
+
+// noEOF converts io.EOF into io.ErrUnexpectedEOF for reads that started a block.
+func noEOF(err error) error {
+	if errors.Is(err, io.EOF) {
+		return io.ErrUnexpectedEOF
+	}
+
+	return err
+}
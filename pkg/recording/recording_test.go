@@ -0,0 +1,304 @@
+package recording_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/hwinfoshmem"
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/hwinfosim"
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/recording"
+	"io"
+	"math"
+	"testing"
+	"time"
+)
+
+func Example() {
+	var buffer bytes.Buffer
+	writer := recording.NewWriter(&buffer)
+	writer.Hostname = "workstation"
+
+	sensor := &hwinfoshmem.Sensor{SensorId: 0xf0000300, SensorName: "CPU [#0]: AMD Ryzen 9 7950X"}
+	reading := &hwinfoshmem.Reading{Sensor: sensor, Type: hwinfoshmem.SENSOR_TYPE_TEMP, UserLabel: "CPU (Tctl/Tdie)", Unit: "°C"}
+	snapshot := &hwinfoshmem.Snapshot{Active: true, Sensors: []*hwinfoshmem.Sensor{sensor}, Readings: []*hwinfoshmem.Reading{reading}}
+
+	for i, value := range []float64{45, 47.5, 47.5} {
+		snapshot.LastUpdate = time.Unix(1694966200+2*int64(i), 0)
+		reading.Value = value
+		if err := writer.Write(snapshot); err != nil {
+			fmt.Printf("Failed to write: %s\n", err)
+			return
+		}
+	}
+
+	reader, err := recording.NewReader(&buffer)
+	if err != nil {
+		fmt.Printf("Failed to open recording: %s\n", err)
+		return
+	}
+
+	fmt.Println(reader.Hostname)
+	for {
+		snapshot, err := reader.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			fmt.Printf("Failed to read: %s\n", err)
+			return
+		}
+
+		fmt.Printf("%d %s: %.1f %s\n", snapshot.LastUpdate.Unix(), snapshot.Readings[0].UserLabel, snapshot.Readings[0].Value, snapshot.Readings[0].Unit)
+	}
+
+	// Output:
+	// workstation
+	// 1694966200 CPU (Tctl/Tdie): 45.0 °C
+	// 1694966202 CPU (Tctl/Tdie): 47.5 °C
+	// 1694966204 CPU (Tctl/Tdie): 47.5 °C
+}
+
+// recordSimulation writes the given amount of polls of a simulator to a recording and returns
+// the recording together with the snapshots written.
+// Halfway through, a sensor is added to change the topology.
+func recordSimulation(t *testing.T, polls int) ([]byte, []*hwinfoshmem.Snapshot) {
+	now := time.Unix(1694966200, 0)
+	simulator := hwinfosim.NewSimulator(&hwinfosim.MemoryTarget{})
+	simulator.Now = func() time.Time {
+		return now
+	}
+
+	addSensor := func(instance uint32) {
+		simulator.AddSensor(&hwinfosim.Sensor{
+			SensorId:           0xf0000300,
+			SensorInstance:     instance,
+			SensorNameOriginal: "CPU [#0]: AMD Ryzen 9 7950X",
+			SensorName:         fmt.Sprintf("CPU %d", instance),
+			Readings: []*hwinfosim.Reading{
+				{
+					Type:      hwinfoshmem.SENSOR_TYPE_TEMP,
+					Id:        0x1000000,
+					UserLabel: "CPU (Tctl/Tdie)",
+					Unit:      "°C",
+					Generator: hwinfosim.RandomWalk(45, 0.25, 30, 95, int64(instance)),
+				},
+				{
+					Type:      hwinfoshmem.SENSOR_TYPE_CLOCK,
+					Id:        0x6000000,
+					UserLabel: "Core 0 Clock",
+					Unit:      "MHz",
+					Generator: hwinfosim.Sine(4200, 800, time.Minute),
+				},
+				{
+					Type:      hwinfoshmem.SENSOR_TYPE_VOLT,
+					Id:        0x2000000,
+					UserLabel: "Vcore",
+					Unit:      "V",
+					Generator: hwinfosim.Constant(1.25),
+				},
+			},
+		})
+	}
+	addSensor(0)
+
+	var buffer bytes.Buffer
+	writer := recording.NewWriter(&buffer)
+	writer.Hostname = "workstation"
+
+	snapshots := make([]*hwinfoshmem.Snapshot, 0, polls)
+	for i := 0; i < polls; i++ {
+		switch {
+		case i == polls/2:
+			addSensor(1)
+			now = now.Add(24 * time.Hour)
+		case i%10 == 0:
+			now = now.Add(2*time.Second + 13*time.Millisecond)
+		default:
+			now = now.Add(2 * time.Second)
+		}
+
+		if err := simulator.Update(); err != nil {
+			t.Fatalf("failed to update simulator: %v", err)
+		}
+
+		snapshot := simulator.Snapshot()
+		if err := writer.Write(snapshot); err != nil {
+			t.Fatalf("failed to write snapshot %d: %v", i, err)
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+
+	return buffer.Bytes(), snapshots
+}
+
+func compareSnapshots(t *testing.T, expected *hwinfoshmem.Snapshot, actual *hwinfoshmem.Snapshot) {
+	t.Helper()
+
+	if !actual.LastUpdate.Equal(expected.LastUpdate) {
+		t.Errorf("expected last update %s, got %s", expected.LastUpdate, actual.LastUpdate)
+	}
+
+	if actual.Active != expected.Active || actual.Status != expected.Status ||
+		actual.Version != expected.Version || actual.Revision != expected.Revision ||
+		actual.PollingPeriod != expected.PollingPeriod || actual.Layout != expected.Layout {
+		t.Errorf("header differs, expected %+v, got %+v", expected, actual)
+	}
+
+	if len(actual.Sensors) != len(expected.Sensors) || len(actual.Readings) != len(expected.Readings) {
+		t.Fatalf(
+			"expected %d sensors and %d readings, got %d and %d",
+			len(expected.Sensors), len(expected.Readings), len(actual.Sensors), len(actual.Readings),
+		)
+	}
+
+	for i, sensor := range actual.Sensors {
+		if len(sensor.Readings) != len(expected.Sensors[i].Readings) {
+			t.Errorf("expected sensor %d to have %d readings, got %d", i, len(expected.Sensors[i].Readings), len(sensor.Readings))
+		}
+
+		if sensor.SensorId != expected.Sensors[i].SensorId || sensor.SensorName != expected.Sensors[i].SensorName {
+			t.Errorf("sensor %d differs, expected %+v, got %+v", i, expected.Sensors[i], sensor)
+		}
+	}
+
+	for i, reading := range actual.Readings {
+		expectedReading := expected.Readings[i]
+		if reading.Sensor.Index != expectedReading.Sensor.Index || reading.Id != expectedReading.Id ||
+			reading.Type != expectedReading.Type || reading.UserLabel != expectedReading.UserLabel ||
+			reading.Unit != expectedReading.Unit {
+			t.Errorf("reading %d differs, expected %+v, got %+v", i, expectedReading, reading)
+		}
+
+		for _, values := range [][2]float64{
+			{expectedReading.Value, reading.Value},
+			{expectedReading.ValueMin, reading.ValueMin},
+			{expectedReading.ValueMax, reading.ValueMax},
+			{expectedReading.ValueAvg, reading.ValueAvg},
+		} {
+			if math.Float64bits(values[0]) != math.Float64bits(values[1]) {
+				t.Errorf("value of reading %d differs, expected %v, got %v", i, values[0], values[1])
+			}
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	data, snapshots := recordSimulation(t, 200)
+
+	reader, err := recording.NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("failed to open recording: %v", err)
+	}
+
+	if reader.Hostname != "workstation" || reader.FormatVersion != recording.FormatVersion {
+		t.Errorf("unexpected header %q, version %d", reader.Hostname, reader.FormatVersion)
+	}
+
+	for i, expected := range snapshots {
+		actual, err := reader.Read()
+		if err != nil {
+			t.Fatalf("failed to read snapshot %d: %v", i, err)
+		}
+
+		compareSnapshots(t, expected, actual)
+	}
+
+	if _, err = reader.Read(); err != io.EOF {
+		t.Errorf("expected io.EOF, got %v", err)
+	}
+
+	image, err := hwinfoshmem.EncodeSnapshot(snapshots[len(snapshots)-1], 1252)
+	if err != nil {
+		t.Fatalf("failed to encode snapshot: %v", err)
+	}
+
+	if len(data)*20 > len(image)*len(snapshots) {
+		t.Errorf("expected recording of %d bytes to be much smaller than %d images of %d bytes", len(data), len(snapshots), len(image))
+	}
+}
+
+func TestUnchangedTopologyIsNotRepeated(t *testing.T) {
+	var buffer bytes.Buffer
+	writer := recording.NewWriter(&buffer)
+
+	reading := &hwinfoshmem.Reading{UserLabel: "A rather long label that would be costly to repeat every poll"}
+	snapshot := &hwinfoshmem.Snapshot{LastUpdate: time.Unix(1694966200, 0), Readings: []*hwinfoshmem.Reading{reading}}
+
+	if err := writer.Write(snapshot); err != nil {
+		t.Fatalf("failed to write: %v", err)
+	}
+	size := buffer.Len()
+
+	snapshot.LastUpdate = snapshot.LastUpdate.Add(2 * time.Second)
+	if err := writer.Write(snapshot); err != nil {
+		t.Fatalf("failed to write: %v", err)
+	}
+
+	if frameSize := buffer.Len() - size; frameSize > 16 {
+		t.Errorf("expected a small frame, got %d bytes", frameSize)
+	}
+
+	reading.UserLabel = "Renamed"
+	if err := writer.Write(snapshot); err != nil {
+		t.Fatalf("failed to write: %v", err)
+	}
+
+	reader, err := recording.NewReader(&buffer)
+	if err != nil {
+		t.Fatalf("failed to open recording: %v", err)
+	}
+
+	longLabel := "A rather long label that would be costly to repeat every poll"
+	for _, expected := range []string{longLabel, longLabel, "Renamed"} {
+		actual, err := reader.Read()
+		if err != nil {
+			t.Fatalf("failed to read: %v", err)
+		}
+
+		if actual.Readings[0].UserLabel != expected {
+			t.Errorf("expected label %q, got %q", expected, actual.Readings[0].UserLabel)
+		}
+	}
+}
+
+func TestTruncatedRecording(t *testing.T) {
+	data, snapshots := recordSimulation(t, 10)
+
+	reader, err := recording.NewReader(bytes.NewReader(data[:len(data)-3]))
+	if err != nil {
+		t.Fatalf("failed to open recording: %v", err)
+	}
+
+	for i := 0; i < len(snapshots)-1; i++ {
+		if _, err = reader.Read(); err != nil {
+			t.Fatalf("failed to read snapshot %d: %v", i, err)
+		}
+	}
+
+	if _, err = reader.Read(); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("expected io.ErrUnexpectedEOF, got %v", err)
+	}
+}
+
+func TestCorruptRecording(t *testing.T) {
+	data, _ := recordSimulation(t, 10)
+
+	corrupt := bytes.Clone(data)
+	corrupt[len(corrupt)-8] ^= 0xff
+
+	reader, err := recording.NewReader(bytes.NewReader(corrupt))
+	if err != nil {
+		t.Fatalf("failed to open recording: %v", err)
+	}
+
+	for err == nil {
+		_, err = reader.Read()
+	}
+
+	if !errors.Is(err, recording.ErrInvalidRecording) {
+		t.Errorf("expected ErrInvalidRecording, got %v", err)
+	}
+
+	if _, err = recording.NewReader(bytes.NewReader(data[1:])); !errors.Is(err, recording.ErrInvalidRecording) {
+		t.Errorf("expected ErrInvalidRecording for missing magic bytes, got %v", err)
+	}
+}
@@ -0,0 +1,88 @@
+package recording
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/hwinfoshmem"
+	"io"
+	"os"
+)
+
+// Writer appends snapshots to a recording.
+// The topology is written when it differs from the previous snapshot, after which only the time
+// and values of each snapshot are written.
+//
+// Each call to [Writer.Write] results in a single call to Write of the underlying writer so that
+// a recording cut short, e.g. by a crash, can be read up to the last complete snapshot.
+//
+// Writer has an initializer function, [NewWriter].
+type Writer struct {
+	// The name of the machine the snapshots originate from. It is written when writing the first
+	// snapshot.
+	Hostname string
+
+	writer      io.Writer
+	wroteHeader bool
+
+	// topology is the encoded topology of the previous snapshot.
+	topology []byte
+
+	// scratch holds the encoded topology of the current snapshot for comparison.
+	scratch []byte
+
+	time    timeColumn
+	columns []floatColumn
+	frame   bitWriter
+	block   []byte
+}
+
+// NewWriter creates a writer with the host name of this machine.
+func NewWriter(writer io.Writer) *Writer {
+	hostname, _ := os.Hostname()
+
+	return &Writer{
+		Hostname: hostname,
+		writer:   writer,
+	}
+}
+
+// Write appends the snapshot. Its time is stored with millisecond precision.
+func (writer *Writer) Write(snapshot *hwinfoshmem.Snapshot) error {
+	writer.block = writer.block[:0]
+
+	if !writer.wroteHeader {
+		writer.block = append(writer.block, magic[:]...)
+		writer.block = binary.AppendUvarint(writer.block, FormatVersion)
+		writer.block = appendString(writer.block, writer.Hostname)
+	}
+
+	writer.scratch = appendTopology(writer.scratch[:0], snapshot)
+	if !bytes.Equal(writer.scratch, writer.topology) {
+		writer.block = appendBlock(writer.block, blockTopology, writer.scratch)
+		writer.topology, writer.scratch = writer.scratch, writer.topology
+
+		writer.time = timeColumn{}
+		writer.columns = make([]floatColumn, 4*len(snapshot.Readings))
+	}
+
+	writer.frame.reset()
+	writer.time.write(&writer.frame, snapshot.LastUpdate.UnixMilli())
+	for i, reading := range snapshot.Readings {
+		writer.columns[4*i].write(&writer.frame, reading.Value)
+		writer.columns[4*i+1].write(&writer.frame, reading.ValueMin)
+		writer.columns[4*i+2].write(&writer.frame, reading.ValueMax)
+		writer.columns[4*i+3].write(&writer.frame, reading.ValueAvg)
+	}
+	writer.block = appendBlock(writer.block, blockFrame, writer.frame.bytes)
+
+	if _, err := writer.writer.Write(writer.block); err != nil {
+		// The compression state no longer matches what was written, start over with a topology.
+		writer.topology = writer.topology[:0]
+		return fmt.Errorf("error writing recording: %w", err)
+	}
+
+	writer.wroteHeader = true
+
+	return nil
+}
@@ -0,0 +1,12 @@
+/*
+Package replay plays back recorded HWiNFO data as if HWiNFO were running, e.g. to debug alerting
+without access to the machine the data was recorded on.
+
+A [Player] provides the same functions as [hwinfoshmem.MemoryReader], code written against
+[hwinfoshmem.Reader], [hwinfoshmem.Source], or [hwinfoshmem.Watcher] runs unmodified against it.
+The snapshots are replayed in real time, faster or slower, or one at a time using [Player.Step].
+They can come from a compressed recording, see
+[github.com/MatthiasKunnen/hwinfo-go/pkg/recording.Reader], or from a series of copies of the
+shared memory, see [Dumps] and [DumpFiles].
+*/
+package replay
@@ -0,0 +1,79 @@
+package replay
+
+import (
+	"fmt"
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/hwinfoshmem"
+	"io"
+	"os"
+)
+
+// Frames provides the snapshots to replay, e.g. a
+// [github.com/MatthiasKunnen/hwinfo-go/pkg/recording.Reader].
+type Frames interface {
+	// Read returns the next snapshot, or io.EOF after the last one.
+	// The snapshot is owned by the caller.
+	Read() (*hwinfoshmem.Snapshot, error)
+}
+
+// imageFrames reads snapshots from copies of the shared memory.
+type imageFrames struct {
+	count int
+	image func(i int) ([]byte, error)
+
+	position int
+}
+
+func (frames *imageFrames) Read() (*hwinfoshmem.Snapshot, error) {
+	if frames.position >= frames.count {
+		return nil, io.EOF
+	}
+
+	position := frames.position
+	frames.position++
+
+	image, err := frames.image(position)
+	if err != nil {
+		return nil, err
+	}
+
+	reader := hwinfoshmem.NewBytesReader(image)
+	info, err := reader.GetHeader()
+	if err != nil {
+		return nil, fmt.Errorf("error reading dump %d: %w", position, err)
+	}
+
+	snapshot, err := hwinfoshmem.NewSnapshot(reader, info)
+	if err != nil {
+		return nil, fmt.Errorf("error reading dump %d: %w", position, err)
+	}
+
+	return snapshot, nil
+}
+
+// Dumps returns the frames contained in copies of the shared memory, e.g. made using
+// [hwinfoshmem.MemoryReader.Copy], in the given order.
+// The copies can also be snapshot files, see [hwinfoshmem.SnapshotFile].
+func Dumps(images ...[]byte) Frames {
+	return &imageFrames{
+		count: len(images),
+		image: func(i int) ([]byte, error) {
+			return images[i], nil
+		},
+	}
+}
+
+// DumpFiles is like [Dumps] but reads the copies from files, e.g. created by print-sensors.
+// Each file is read when it is its turn to be replayed.
+func DumpFiles(paths ...string) Frames {
+	return &imageFrames{
+		count: len(paths),
+		image: func(i int) ([]byte, error) {
+			image, err := os.ReadFile(paths[i])
+			if err != nil {
+				return nil, fmt.Errorf("error reading dump: %w", err)
+			}
+
+			return image, nil
+		},
+	}
+}
@@ -0,0 +1,301 @@
+package replay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/hwinfoshmem"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unsafe"
+)
+
+// Player replays snapshots by writing them in HWiNFO's shared memory layout, one at a time, into
+// memory that is read using the embedded [hwinfoshmem.Reader].
+//
+// The LastUpdate of the first replayed snapshot is set to the time it is replayed, the LastUpdate of
+// the following snapshots advances by the recorded intervals divided by the speed, like the delays
+// of [Player.Run]. As a result, the replay looks live, e.g. to staleness checks. The polling period
+// is also divided by the speed so that watchers poll at the pace of the replay.
+// After the last snapshot, the status is set to inactive like HWiNFO does when it exits.
+//
+// Like [hwinfoshmem.MemoryReader], the lock must be held while reading. While it is held, the
+// replay pauses.
+//
+// Player has an initializer function, [NewPlayer].
+type Player struct {
+	// Frames provides the snapshots to replay.
+	Frames Frames
+
+	// Speed of the replay used by [Player.Run], e.g. 1 for real time and 10 to replay ten times
+	// faster than recorded.
+	Speed float64
+
+	// Now returns the current time, used as the LastUpdate of the first snapshot. Can be replaced
+	// to control time in tests.
+	Now func() time.Time
+
+	*hwinfoshmem.Reader
+
+	// lock is held by readers, replacing the image waits until it is released.
+	lock   sync.Mutex
+	locked atomic.Bool
+
+	// mutex protects the fields below. Step holds it while waiting for the lock so that Step and
+	// Run are not interleaved.
+	mutex sync.Mutex
+	image []byte
+
+	// recordedUpdate is the original LastUpdate of the snapshot that is currently replayed.
+	recordedUpdate time.Time
+
+	// recordedPollingPeriod is the original polling period of the snapshot that is currently
+	// replayed.
+	recordedPollingPeriod time.Duration
+
+	// next is the snapshot that was read ahead by Run to determine when to replay it.
+	next *hwinfoshmem.Snapshot
+
+	// current is the snapshot that is currently replayed, after rewriting its fields.
+	current *hwinfoshmem.Snapshot
+}
+
+var _ hwinfoshmem.Backend = (*Player)(nil)
+
+// NewPlayer creates a player that replays the frames in real time.
+func NewPlayer(frames Frames) *Player {
+	player := &Player{
+		Frames: frames,
+		Speed:  1,
+		Now:    time.Now,
+	}
+	player.Reader = &hwinfoshmem.Reader{
+		GetPointer: func() (uintptr, error) {
+			if !player.IsLocked() {
+				return 0, hwinfoshmem.ErrNotLocked
+			}
+
+			if len(player.image) == 0 {
+				return 0, errors.New("nothing replayed yet, use Open first")
+			}
+
+			return uintptr(unsafe.Pointer(&player.image[0])), nil
+		},
+		GetSize: func() (uintptr, error) {
+			return uintptr(len(player.image)), nil
+		},
+	}
+
+	return player
+}
+
+// Open replays the first snapshot when nothing has been replayed yet.
+// Returns io.EOF when there are no snapshots.
+func (player *Player) Open() error {
+	player.mutex.Lock()
+	defer player.mutex.Unlock()
+
+	if player.image != nil {
+		return nil
+	}
+
+	return player.step()
+}
+
+// Close releases the lock. The position in the replay is kept.
+func (player *Player) Close() error {
+	return player.ReleaseLock()
+}
+
+// Lock acquires the lock, pausing the replay until it is released.
+// Release it using [Player.ReleaseLock].
+func (player *Player) Lock() error {
+	player.lock.Lock()
+	player.locked.Store(true)
+
+	return nil
+}
+
+// ReleaseLock releases the lock.
+// Returns nil when: the lock is successfully released or the lock was not held.
+func (player *Player) ReleaseLock() error {
+	if player.locked.CompareAndSwap(true, false) {
+		player.lock.Unlock()
+	}
+
+	return nil
+}
+
+// IsLocked reports whether the lock is currently held.
+func (player *Player) IsLocked() bool {
+	return player.locked.Load()
+}
+
+// Step replays the next snapshot, waiting until the lock is released.
+// After the last snapshot, the status is set to inactive and io.EOF is returned.
+func (player *Player) Step() error {
+	player.mutex.Lock()
+	defer player.mutex.Unlock()
+
+	return player.step()
+}
+
+// Run replays the snapshots at [Player.Speed], keeping the intervals between the recorded
+// LastUpdate times. Intervals that are not positive, e.g. between unrelated dumps, are replaced by
+// the recorded polling period.
+// When the context is done, the status is set to inactive, like HWiNFO does when it exits.
+// Returns nil after the last snapshot or when stopped by the context.
+func (player *Player) Run(ctx context.Context) error {
+	if player.Speed <= 0 {
+		return fmt.Errorf("invalid replay speed %v, must be positive", player.Speed)
+	}
+
+	if err := player.Open(); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		return err
+	}
+
+	for {
+		delay, err := player.nextDelay()
+		end := errors.Is(err, io.EOF)
+		if err != nil && !end {
+			return err
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return player.SetActive(false)
+		case <-timer.C:
+		}
+
+		if end {
+			return player.SetActive(false)
+		}
+
+		if err = player.Step(); err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+	}
+}
+
+// SetActive changes the status of the snapshot that is currently replayed to "HWiS" when active
+// is true and to "DAED" otherwise, waiting until the lock is released.
+func (player *Player) SetActive(active bool) error {
+	player.mutex.Lock()
+	defer player.mutex.Unlock()
+
+	return player.setActive(active)
+}
+
+// setActive changes the status of the current snapshot. The mutex must be held.
+func (player *Player) setActive(active bool) error {
+	if player.current == nil || player.current.Active == active {
+		return nil
+	}
+
+	player.current.Active = active
+	player.current.Status = ""
+
+	return player.publish(player.current)
+}
+
+// nextDelay reads the next snapshot ahead and returns how long to wait before replaying it.
+// When reading fails, e.g. with io.EOF, the delay is the recorded polling period.
+func (player *Player) nextDelay() (time.Duration, error) {
+	player.mutex.Lock()
+	defer player.mutex.Unlock()
+
+	var err error
+	if player.next == nil {
+		player.next, err = player.Frames.Read()
+	}
+
+	if err != nil {
+		// Keep the last snapshot for a polling period, like HWiNFO before it exits.
+		return player.scale(player.interval(nil)), err
+	}
+
+	return player.scale(player.interval(player.next)), nil
+}
+
+// scale divides the duration by the speed. When the speed is not positive, the duration is
+// returned unchanged.
+func (player *Player) scale(duration time.Duration) time.Duration {
+	if player.Speed <= 0 {
+		return duration
+	}
+
+	return time.Duration(float64(duration) / player.Speed)
+}
+
+// interval returns the recorded time between the current and the next snapshot. When that is not
+// positive or next is nil, the recorded polling period is used instead. The mutex must be held.
+func (player *Player) interval(next *hwinfoshmem.Snapshot) time.Duration {
+	var interval time.Duration
+	if next != nil {
+		interval = next.LastUpdate.Sub(player.recordedUpdate)
+	}
+	if interval <= 0 {
+		interval = player.recordedPollingPeriod
+	}
+	if interval <= 0 {
+		interval = hwinfoshmem.DefaultPollingPeriod
+	}
+
+	return interval
+}
+
+// step replays the next snapshot. The mutex must be held.
+func (player *Player) step() error {
+	snapshot := player.next
+	player.next = nil
+
+	if snapshot == nil {
+		var err error
+		snapshot, err = player.Frames.Read()
+		if errors.Is(err, io.EOF) {
+			return errors.Join(io.EOF, player.setActive(false))
+		} else if err != nil {
+			return err
+		}
+	}
+
+	lastUpdate := player.Now()
+	if player.current != nil {
+		lastUpdate = player.current.LastUpdate.Add(player.scale(player.interval(snapshot)))
+	}
+
+	player.recordedUpdate = snapshot.LastUpdate
+	player.recordedPollingPeriod = snapshot.PollingPeriod
+
+	snapshot.LastUpdate = lastUpdate
+	if player.Speed > 0 {
+		snapshot.PollingPeriod = player.scale(snapshot.PollingPeriod)
+		if snapshot.PollingPeriod < time.Millisecond && player.recordedPollingPeriod > 0 {
+			snapshot.PollingPeriod = time.Millisecond
+		}
+	}
+	player.current = snapshot
+
+	return player.publish(snapshot)
+}
+
+// publish replaces the image once the lock is released. The mutex must be held.
+func (player *Player) publish(snapshot *hwinfoshmem.Snapshot) error {
+	image, err := hwinfoshmem.EncodeSnapshot(snapshot, player.GetCodepage())
+	if err != nil {
+		return fmt.Errorf("error encoding snapshot: %w", err)
+	}
+
+	player.lock.Lock()
+	player.image = image
+	player.lock.Unlock()
+
+	return nil
+}
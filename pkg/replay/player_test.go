@@ -0,0 +1,315 @@
+package replay_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/hwinfoshmem"
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/recording"
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/replay"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+var recordedStart = time.Unix(1694966200, 0)
+
+// newTestSnapshots returns snapshots with a single temperature reading, recorded every 2 seconds,
+// of which the values are 40, 41, 42, etc.
+func newTestSnapshots(count int) []*hwinfoshmem.Snapshot {
+	snapshots := make([]*hwinfoshmem.Snapshot, count)
+
+	for i := range snapshots {
+		sensor := &hwinfoshmem.Sensor{SensorId: 0xf0000300, SensorName: "CPU [#0]: AMD Ryzen 9 7950X"}
+		reading := &hwinfoshmem.Reading{
+			Sensor:    sensor,
+			Type:      hwinfoshmem.SENSOR_TYPE_TEMP,
+			Id:        0x1000000,
+			UserLabel: "CPU (Tctl/Tdie)",
+			Unit:      "°C",
+			Value:     40 + float64(i),
+		}
+		sensor.Readings = []*hwinfoshmem.Reading{reading}
+
+		snapshots[i] = &hwinfoshmem.Snapshot{
+			Active:        true,
+			Version:       2,
+			Revision:      1,
+			LastUpdate:    recordedStart.Add(time.Duration(i) * 2 * time.Second),
+			PollingPeriod: 2 * time.Second,
+			Sensors:       []*hwinfoshmem.Sensor{sensor},
+			Readings:      []*hwinfoshmem.Reading{reading},
+		}
+	}
+
+	return snapshots
+}
+
+func newTestDumps(t *testing.T, count int) [][]byte {
+	images := make([][]byte, count)
+
+	for i, snapshot := range newTestSnapshots(count) {
+		image, err := hwinfoshmem.EncodeSnapshot(snapshot, 1252)
+		if err != nil {
+			t.Fatalf("failed to encode snapshot: %v", err)
+		}
+		images[i] = image
+	}
+
+	return images
+}
+
+// readTemperature reads the header and the value of the first reading while holding the lock.
+func readTemperature(t *testing.T, player *replay.Player) (*hwinfoshmem.HwinfoHeader, float64) {
+	t.Helper()
+
+	if err := player.Lock(); err != nil {
+		t.Fatalf("failed to lock: %v", err)
+	}
+	defer player.ReleaseLock()
+
+	info, err := player.GetHeader()
+	if err != nil {
+		t.Fatalf("failed to get header: %v", err)
+	}
+
+	readings, err := player.GetReadings(info)
+	if err != nil {
+		t.Fatalf("failed to get readings: %v", err)
+	}
+
+	header := *info
+	return &header, readings[0].Value.ToFloat64()
+}
+
+func ExamplePlayer() {
+	var buffer bytes.Buffer
+	writer := recording.NewWriter(&buffer)
+	for _, snapshot := range newTestSnapshots(3) {
+		if err := writer.Write(snapshot); err != nil {
+			fmt.Printf("Failed to write recording: %s\n", err)
+			return
+		}
+	}
+
+	reader, err := recording.NewReader(&buffer)
+	if err != nil {
+		fmt.Printf("Failed to open recording: %s\n", err)
+		return
+	}
+
+	player := replay.NewPlayer(reader)
+
+	// Use player.Run(ctx) to replay in real time.
+	err = player.Open()
+	for err == nil {
+		_ = player.Lock()
+		hwInfo, _ := player.GetHeader()
+		readings, _ := player.GetReadings(hwInfo)
+		fmt.Printf("%s: %.1f %s\n", readings[0].UserLabel, readings[0].Value.ToFloat64(), readings[0].Unit)
+		_ = player.ReleaseLock()
+
+		err = player.Step()
+	}
+
+	if !errors.Is(err, io.EOF) {
+		fmt.Printf("Failed to replay: %s\n", err)
+	}
+
+	// Output:
+	// CPU (Tctl/Tdie): 40.0 °C
+	// CPU (Tctl/Tdie): 41.0 °C
+	// CPU (Tctl/Tdie): 42.0 °C
+}
+
+func TestPlayerStep(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	player := replay.NewPlayer(replay.Dumps(newTestDumps(t, 3)...))
+	player.Speed = 2
+	player.Now = func() time.Time {
+		return now
+	}
+
+	if err := player.Open(); err != nil {
+		t.Fatalf("failed to open: %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if i > 0 {
+			if err := player.Step(); err != nil {
+				t.Fatalf("failed to step: %v", err)
+			}
+		}
+
+		info, value := readTemperature(t, player)
+		if value != 40+float64(i) {
+			t.Errorf("expected value %d, got %v", 40+i, value)
+		}
+
+		// The recorded interval of 2 seconds is replayed twice as fast.
+		if expected := now.Add(time.Duration(i) * time.Second); !info.GetLastUpdateTime().Equal(expected) {
+			t.Errorf("expected last update %s, got %s", expected, info.GetLastUpdateTime())
+		}
+
+		if info.PollingPeriodInMs != 1000 {
+			t.Errorf("expected polling period of 1000 ms, got %d", info.PollingPeriodInMs)
+		}
+
+		if !info.IsActive() {
+			t.Error("expected active status")
+		}
+	}
+
+	if err := player.Step(); !errors.Is(err, io.EOF) {
+		t.Fatalf("expected io.EOF, got %v", err)
+	}
+
+	if info, value := readTemperature(t, player); info.IsActive() || value != 42 {
+		t.Errorf("expected the last snapshot to be inactive, got status %s and value %v", info.GetStatus(), value)
+	}
+}
+
+func TestPlayerRequiresLock(t *testing.T) {
+	player := replay.NewPlayer(replay.Dumps(newTestDumps(t, 1)...))
+	if err := player.Open(); err != nil {
+		t.Fatalf("failed to open: %v", err)
+	}
+
+	if _, err := player.GetHeader(); !errors.Is(err, hwinfoshmem.ErrNotLocked) {
+		t.Errorf("expected ErrNotLocked, got %v", err)
+	}
+}
+
+func TestPlayerPausesWhileLocked(t *testing.T) {
+	player := replay.NewPlayer(replay.Dumps(newTestDumps(t, 2)...))
+	if err := player.Open(); err != nil {
+		t.Fatalf("failed to open: %v", err)
+	}
+
+	if err := player.Lock(); err != nil {
+		t.Fatalf("failed to lock: %v", err)
+	}
+
+	stepped := make(chan error)
+	go func() {
+		stepped <- player.Step()
+	}()
+
+	select {
+	case err := <-stepped:
+		t.Fatalf("expected step to wait for the lock, got %v", err)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	_ = player.ReleaseLock()
+	if err := <-stepped; err != nil {
+		t.Fatalf("failed to step: %v", err)
+	}
+
+	if _, value := readTemperature(t, player); value != 41 {
+		t.Errorf("expected value 41, got %v", value)
+	}
+}
+
+func TestPlayerWithWatcher(t *testing.T) {
+	player := replay.NewPlayer(replay.Dumps(newTestDumps(t, 5)...))
+	player.Speed = 100
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	runErr := make(chan error, 1)
+	go func() {
+		runErr <- player.Run(ctx)
+	}()
+
+	// Wait for the first snapshot so that the watcher does not read before Open.
+	for {
+		if err := player.Lock(); err != nil {
+			t.Fatalf("failed to lock: %v", err)
+		}
+		_, err := player.GetHeader()
+		_ = player.ReleaseLock()
+
+		if err == nil {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	var values []float64
+	var lastValue float64
+	var lastUpdate time.Time
+
+	for event := range hwinfoshmem.NewWatcher(player).Watch(ctx) {
+		if event.Kind == hwinfoshmem.WatchEventInactive {
+			lastValue = event.Snapshot.Readings[0].Value
+			break
+		}
+
+		if event.Kind != hwinfoshmem.WatchEventSnapshot {
+			t.Fatalf("unexpected %s event: %v", event.Kind, event.Err)
+		}
+
+		if !event.Snapshot.LastUpdate.After(lastUpdate) {
+			t.Errorf("expected last update after %s, got %s", lastUpdate, event.Snapshot.LastUpdate)
+		}
+		lastUpdate = event.Snapshot.LastUpdate
+
+		value := event.Snapshot.Readings[0].Value
+		if len(values) > 0 && value <= values[len(values)-1] {
+			t.Errorf("expected values to increase, got %v after %v", value, values)
+		}
+		values = append(values, value)
+	}
+
+	if len(values) == 0 || lastValue != 44 {
+		t.Errorf("expected to observe values and to end at 44, got %v and %v", values, lastValue)
+	}
+
+	if err := <-runErr; err != nil {
+		t.Errorf("failed to run: %v", err)
+	}
+}
+
+func TestDumpFiles(t *testing.T) {
+	directory := t.TempDir()
+	paths := make([]string, 0)
+
+	for i, image := range newTestDumps(t, 2) {
+		file := hwinfoshmem.NewSnapshotFile(image)
+		encoded, err := file.MarshalBinary()
+		if err != nil {
+			t.Fatalf("failed to encode snapshot file: %v", err)
+		}
+
+		path := filepath.Join(directory, fmt.Sprintf("dump%d.bin", i))
+		if err = os.WriteFile(path, encoded, 0o644); err != nil {
+			t.Fatalf("failed to write dump: %v", err)
+		}
+		paths = append(paths, path)
+	}
+
+	frames := replay.DumpFiles(append(paths, filepath.Join(directory, "missing.bin"))...)
+	for i := 0; i < 2; i++ {
+		snapshot, err := frames.Read()
+		if err != nil {
+			t.Fatalf("failed to read dump %d: %v", i, err)
+		}
+
+		if snapshot.Readings[0].Value != 40+float64(i) {
+			t.Errorf("expected value %d, got %v", 40+i, snapshot.Readings[0].Value)
+		}
+	}
+
+	if _, err := frames.Read(); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected os.ErrNotExist, got %v", err)
+	}
+
+	if _, err := frames.Read(); err != io.EOF {
+		t.Errorf("expected io.EOF, got %v", err)
+	}
+}
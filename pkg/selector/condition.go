@@ -0,0 +1,229 @@
+package selector
+
+import (
+	"fmt"
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/hwinfoshmem"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type node interface {
+	match(reading *hwinfoshmem.Reading) bool
+}
+
+type matchAll struct{}
+
+func (matchAll) match(*hwinfoshmem.Reading) bool {
+	return true
+}
+
+type and struct {
+	left  node
+	right node
+}
+
+func (n and) match(reading *hwinfoshmem.Reading) bool {
+	return n.left.match(reading) && n.right.match(reading)
+}
+
+type or struct {
+	left  node
+	right node
+}
+
+func (n or) match(reading *hwinfoshmem.Reading) bool {
+	return n.left.match(reading) || n.right.match(reading)
+}
+
+type not struct {
+	operand node
+}
+
+func (n not) match(reading *hwinfoshmem.Reading) bool {
+	return !n.operand.match(reading)
+}
+
+type textCondition struct {
+	text    func(reading *hwinfoshmem.Reading) string
+	matches func(text string) bool
+}
+
+func (c textCondition) match(reading *hwinfoshmem.Reading) bool {
+	return c.matches(c.text(reading))
+}
+
+type numberCondition struct {
+	number func(reading *hwinfoshmem.Reading) uint32
+	value  uint32
+	negate bool
+}
+
+func (c numberCondition) match(reading *hwinfoshmem.Reading) bool {
+	return (c.number(reading) == c.value) != c.negate
+}
+
+// field is a property of a reading that can be used in a condition.
+// Exactly one of text and number is set.
+type field struct {
+	text   func(reading *hwinfoshmem.Reading) string
+	number func(reading *hwinfoshmem.Reading) uint32
+
+	// parse converts the value of a condition on a number field. When nil, the value is parsed as
+	// a decimal or hexadecimal number.
+	parse func(value string) (uint32, error)
+}
+
+var fields = map[string]field{
+	"sensor": {text: func(reading *hwinfoshmem.Reading) string {
+		if reading.Sensor == nil {
+			return ""
+		}
+		return reading.Sensor.SensorName
+	}},
+	"sensor_original": {text: func(reading *hwinfoshmem.Reading) string {
+		if reading.Sensor == nil {
+			return ""
+		}
+		return reading.Sensor.SensorNameOriginal
+	}},
+	"label": {text: func(reading *hwinfoshmem.Reading) string {
+		return reading.UserLabel
+	}},
+	"original_label": {text: func(reading *hwinfoshmem.Reading) string {
+		return reading.OriginalLabel
+	}},
+	"unit": {text: func(reading *hwinfoshmem.Reading) string {
+		return reading.Unit
+	}},
+	"type": {
+		number: func(reading *hwinfoshmem.Reading) uint32 {
+			return uint32(reading.Type)
+		},
+		parse: parseReadingType,
+	},
+	"sensor_id": {number: func(reading *hwinfoshmem.Reading) uint32 {
+		if reading.Sensor == nil {
+			return 0
+		}
+		return reading.Sensor.SensorId
+	}},
+	"instance": {number: func(reading *hwinfoshmem.Reading) uint32 {
+		if reading.Sensor == nil {
+			return 0
+		}
+		return reading.Sensor.SensorInstance
+	}},
+	"id": {number: func(reading *hwinfoshmem.Reading) uint32 {
+		return reading.Id
+	}},
+}
+
+func parseReadingType(value string) (uint32, error) {
+	readingType, err := hwinfoshmem.ParseReadingType(value)
+	if err != nil {
+		return 0, err
+	}
+
+	return uint32(readingType), nil
+}
+
+func parseNumber(value string) (uint32, error) {
+	number, err := strconv.ParseUint(value, 0, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number %q", value)
+	}
+
+	return uint32(number), nil
+}
+
+func (f field) newCondition(operator tokenKind, value string) (node, error) {
+	if f.number != nil {
+		if operator != tokenEqual && operator != tokenNotEqual {
+			return nil, fmt.Errorf("only == and != can be used on numbers")
+		}
+
+		parse := f.parse
+		if parse == nil {
+			parse = parseNumber
+		}
+
+		number, err := parse(value)
+		if err != nil {
+			return nil, err
+		}
+
+		return numberCondition{number: f.number, value: number, negate: operator == tokenNotEqual}, nil
+	}
+
+	condition := textCondition{text: f.text}
+
+	switch operator {
+	case tokenEqual:
+		condition.matches = func(text string) bool { return text == value }
+	case tokenNotEqual:
+		condition.matches = func(text string) bool { return text != value }
+	case tokenGlob, tokenRegex:
+		pattern := value
+		if operator == tokenGlob {
+			pattern = "^(?:" + globToRegex(value) + ")$"
+		}
+
+		compiled, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", value, err)
+		}
+		condition.matches = compiled.MatchString
+	}
+
+	return condition, nil
+}
+
+// globToRegex converts a glob pattern into a regular expression.
+func globToRegex(glob string) string {
+	var builder strings.Builder
+	runes := []rune(glob)
+
+	for i := 0; i < len(runes); i++ {
+		switch r := runes[i]; r {
+		case '*':
+			builder.WriteString(".*")
+		case '?':
+			builder.WriteString(".")
+		case '\\':
+			if i+1 < len(runes) {
+				i++
+				builder.WriteString(regexp.QuoteMeta(string(runes[i])))
+			} else {
+				builder.WriteString(`\\`)
+			}
+		case '[':
+			end := i + 1
+			if end < len(runes) && (runes[end] == '!' || runes[end] == '^') {
+				end++
+			}
+			if end < len(runes) && runes[end] == ']' {
+				end++
+			}
+			for end < len(runes) && runes[end] != ']' {
+				end++
+			}
+
+			if end >= len(runes) {
+				builder.WriteString(`\[`)
+				continue
+			}
+
+			class := string(runes[i+1 : end])
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			builder.WriteString("[" + strings.ReplaceAll(class, `\`, `\\`) + "]")
+			i = end
+		default:
+			builder.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+
+	return builder.String()
+}
@@ -0,0 +1,42 @@
+/*
+Package selector selects readings of a [hwinfoshmem.Snapshot] using expressions such as:
+
+	sensor~"CPU \[#0\]*" && type==temp && label=~"CCD\d"
+
+An expression consists of conditions that compare a field of a reading to a value, combined using
+&& (and), || (or), ! (not), and parentheses. && takes precedence over ||.
+
+# Fields
+
+Text fields:
+  - sensor: the name of the sensor, possibly renamed by the user
+  - sensor_original: the original name of the sensor
+  - label: the label of the reading, possibly renamed by the user
+  - original_label: the original label of the reading
+  - unit: the unit of the reading, e.g. °C
+
+Numeric fields:
+  - type: the type of the reading, by name (temp, volt, fan, current, power, clock, usage, other,
+    none) or number, see [hwinfoshmem.ParseReadingType]
+  - sensor_id: the SensorId of the sensor
+  - instance: the SensorInstance of the sensor
+  - id: the Id of the reading
+
+# Operators
+
+  - == and != compare equality, numbers can be written in decimal or hexadecimal, e.g. 0xf0000300
+  - ~ matches a glob pattern against a text field: * matches any text, ? matches a single
+    character, [...] matches a character class, and a backslash escapes the next character
+  - =~ matches a regular expression, see [regexp/syntax], against a text field
+
+Glob patterns match the whole text. Regular expressions match any part of the text, use ^ and $ to
+match the whole text.
+
+# Values
+
+Values are double-quoted strings or bare words consisting of letters, digits, and the characters
+_ . - :.
+Within a quoted string, \" and \\ are a quote and a backslash, all other backslashes are kept as-is
+so that they can be used in patterns.
+*/
+package selector
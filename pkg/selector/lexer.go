@@ -0,0 +1,132 @@
+package selector
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type tokenKind int
+
+const (
+	tokenEnd tokenKind = iota
+	tokenWord
+	tokenString
+	tokenEqual      // ==
+	tokenNotEqual   // !=
+	tokenGlob       // ~
+	tokenRegex      // =~
+	tokenAnd        // &&
+	tokenOr         // ||
+	tokenNot        // !
+	tokenOpenParen  // (
+	tokenCloseParen // )
+)
+
+type token struct {
+	kind tokenKind
+
+	// The text of the token, unquoted for strings.
+	text string
+
+	// The byte offset of the token in the expression.
+	offset int
+}
+
+// operators are ordered so that longer operators are matched before their prefixes.
+var operators = []struct {
+	text string
+	kind tokenKind
+}{
+	{"==", tokenEqual},
+	{"!=", tokenNotEqual},
+	{"=~", tokenRegex},
+	{"&&", tokenAnd},
+	{"||", tokenOr},
+	{"~", tokenGlob},
+	{"!", tokenNot},
+	{"(", tokenOpenParen},
+	{")", tokenCloseParen},
+}
+
+// tokenize splits the expression into tokens, the last token is always tokenEnd.
+func tokenize(expression string) ([]token, error) {
+	tokens := make([]token, 0)
+	offset := 0
+
+	for offset < len(expression) {
+		r, size := utf8.DecodeRuneInString(expression[offset:])
+		if unicode.IsSpace(r) {
+			offset += size
+			continue
+		}
+
+		if r == '"' {
+			text, length, err := unquote(expression, offset)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: tokenString, text: text, offset: offset})
+			offset += length
+			continue
+		}
+
+		if isWordRune(r) {
+			end := offset
+			for end < len(expression) {
+				r, size := utf8.DecodeRuneInString(expression[end:])
+				if !isWordRune(r) {
+					break
+				}
+				end += size
+			}
+			tokens = append(tokens, token{kind: tokenWord, text: expression[offset:end], offset: offset})
+			offset = end
+			continue
+		}
+
+		matched := false
+		for _, operator := range operators {
+			if strings.HasPrefix(expression[offset:], operator.text) {
+				tokens = append(tokens, token{kind: operator.kind, text: operator.text, offset: offset})
+				offset += len(operator.text)
+				matched = true
+				break
+			}
+		}
+
+		if !matched {
+			return nil, syntaxError(offset, "unexpected character %q", r)
+		}
+	}
+
+	return append(tokens, token{kind: tokenEnd, offset: len(expression)}), nil
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '.' || r == '-' || r == ':'
+}
+
+// unquote returns the content of the quoted string starting at the offset and the length of the
+// quoted string including the quotes.
+func unquote(expression string, offset int) (string, int, error) {
+	var builder strings.Builder
+
+	for i := offset + 1; i < len(expression); i++ {
+		switch c := expression[i]; c {
+		case '"':
+			return builder.String(), i + 1 - offset, nil
+		case '\\':
+			if i+1 < len(expression) && (expression[i+1] == '"' || expression[i+1] == '\\') {
+				i++
+				builder.WriteByte(expression[i])
+			} else {
+				builder.WriteByte(c)
+			}
+		default:
+			builder.WriteByte(c)
+		}
+	}
+
+	return "", 0, syntaxError(offset, "unterminated string")
+}
@@ -0,0 +1,231 @@
+package selector
+
+import (
+	"errors"
+	"fmt"
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/hwinfoshmem"
+	"strings"
+)
+
+// ErrSyntax is returned when compiling an invalid expression.
+var ErrSyntax = errors.New("invalid selector")
+
+func syntaxError(offset int, format string, args ...any) error {
+	return fmt.Errorf("%w at offset %d: %s", ErrSyntax, offset, fmt.Sprintf(format, args...))
+}
+
+// Selector is a compiled expression that selects readings, see the package documentation for the
+// syntax.
+// A Selector can be used concurrently.
+//
+// Selector has initializer functions, [Compile] and [MustCompile].
+type Selector struct {
+	expression string
+	root       node
+}
+
+// Compile parses the expression. An empty expression selects all readings.
+func Compile(expression string) (*Selector, error) {
+	selector := &Selector{expression: expression}
+
+	tokens, err := tokenize(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	if tokens[0].kind == tokenEnd {
+		selector.root = matchAll{}
+		return selector, nil
+	}
+
+	p := &parser{tokens: tokens}
+	selector.root, err = p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if next := p.peek(); next.kind != tokenEnd {
+		return nil, syntaxError(next.offset, "unexpected %q", next.text)
+	}
+
+	return selector, nil
+}
+
+// MustCompile is like [Compile] but panics when the expression is invalid.
+func MustCompile(expression string) *Selector {
+	selector, err := Compile(expression)
+	if err != nil {
+		panic(err)
+	}
+
+	return selector
+}
+
+// Match reports whether the reading is selected.
+// When the reading has no sensor, the sensor fields are empty or zero.
+// The zero Selector selects all readings.
+func (selector *Selector) Match(reading *hwinfoshmem.Reading) bool {
+	if selector.root == nil {
+		return true
+	}
+
+	return selector.root.match(reading)
+}
+
+// Select returns the selected readings of the snapshot in the order reported by HWiNFO.
+func (selector *Selector) Select(snapshot *hwinfoshmem.Snapshot) []*hwinfoshmem.Reading {
+	return selector.Filter(snapshot.Readings)
+}
+
+// Filter returns the selected readings.
+func (selector *Selector) Filter(readings []*hwinfoshmem.Reading) []*hwinfoshmem.Reading {
+	selected := make([]*hwinfoshmem.Reading, 0)
+
+	for _, reading := range readings {
+		if selector.Match(reading) {
+			selected = append(selected, reading)
+		}
+	}
+
+	return selected
+}
+
+// String returns the expression the selector was compiled from.
+func (selector *Selector) String() string {
+	return selector.expression
+}
+
+// MarshalText implements [encoding.TextMarshaler] by returning the expression.
+func (selector *Selector) MarshalText() ([]byte, error) {
+	return []byte(selector.expression), nil
+}
+
+// UnmarshalText implements [encoding.TextUnmarshaler] by compiling the expression.
+func (selector *Selector) UnmarshalText(text []byte) error {
+	compiled, err := Compile(string(text))
+	if err != nil {
+		return err
+	}
+
+	*selector = *compiled
+	return nil
+}
+
+// Set compiles the expression, allowing a Selector to be used as a command line flag, see
+// [flag.Value].
+func (selector *Selector) Set(expression string) error {
+	return selector.UnmarshalText([]byte(expression))
+}
+
+type parser struct {
+	tokens   []token
+	position int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.position]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.position]
+	if t.kind != tokenEnd {
+		p.position++
+	}
+
+	return t
+}
+
+// parseOr parses: and ('||' and)*
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek().kind == tokenOr {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = or{left, right}
+	}
+
+	return left, nil
+}
+
+// parseAnd parses: unary ('&&' unary)*
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek().kind == tokenAnd {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = and{left, right}
+	}
+
+	return left, nil
+}
+
+// parseUnary parses: '!' unary | '(' or ')' | condition
+func (p *parser) parseUnary() (node, error) {
+	switch t := p.peek(); t.kind {
+	case tokenNot:
+		p.next()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return not{operand}, nil
+	case tokenOpenParen:
+		p.next()
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokenCloseParen {
+			return nil, syntaxError(closing.offset, "expected )")
+		}
+		return inner, nil
+	default:
+		return p.parseCondition()
+	}
+}
+
+// parseCondition parses: field operator value
+func (p *parser) parseCondition() (node, error) {
+	fieldToken := p.next()
+	if fieldToken.kind != tokenWord {
+		return nil, syntaxError(fieldToken.offset, "expected field")
+	}
+
+	f, ok := fields[strings.ToLower(fieldToken.text)]
+	if !ok {
+		return nil, syntaxError(fieldToken.offset, "unknown field %q", fieldToken.text)
+	}
+
+	operator := p.next()
+	switch operator.kind {
+	case tokenEqual, tokenNotEqual, tokenGlob, tokenRegex:
+	default:
+		return nil, syntaxError(operator.offset, "expected ==, !=, ~, or =~ after %s", fieldToken.text)
+	}
+
+	value := p.next()
+	if value.kind != tokenWord && value.kind != tokenString {
+		return nil, syntaxError(value.offset, "expected value")
+	}
+
+	condition, err := f.newCondition(operator.kind, value.text)
+	if err != nil {
+		return nil, syntaxError(value.offset, "%s", err)
+	}
+
+	return condition, nil
+}
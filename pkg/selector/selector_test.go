@@ -0,0 +1,161 @@
+package selector_test
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/hwinfoshmem"
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/selector"
+	"testing"
+)
+
+// newTestSnapshot returns a snapshot with a CPU, motherboard, and GPU sensor.
+func newTestSnapshot() *hwinfoshmem.Snapshot {
+	snapshot := &hwinfoshmem.Snapshot{}
+
+	addSensor := func(id uint32, instance uint32, nameOriginal string, name string) *hwinfoshmem.Sensor {
+		sensor := &hwinfoshmem.Sensor{
+			Index:              uint32(len(snapshot.Sensors)),
+			SensorId:           id,
+			SensorInstance:     instance,
+			SensorNameOriginal: nameOriginal,
+			SensorName:         name,
+		}
+		snapshot.Sensors = append(snapshot.Sensors, sensor)
+		return sensor
+	}
+
+	addReading := func(sensor *hwinfoshmem.Sensor, readingType hwinfoshmem.ReadingType, id uint32, originalLabel string, label string, unit string) {
+		reading := &hwinfoshmem.Reading{
+			Sensor:        sensor,
+			Type:          readingType,
+			SensorIndex:   sensor.Index,
+			Id:            id,
+			OriginalLabel: originalLabel,
+			UserLabel:     label,
+			Unit:          unit,
+		}
+		sensor.Readings = append(sensor.Readings, reading)
+		snapshot.Readings = append(snapshot.Readings, reading)
+	}
+
+	cpu := addSensor(0xF0000501, 0, "CPU [#0]: AMD Ryzen 9 7950X: Enhanced", "CPU [#0]: AMD Ryzen 9 7950X: Enhanced")
+	addReading(cpu, hwinfoshmem.SENSOR_TYPE_TEMP, 0x1000000, "CPU (Tctl/Tdie)", "CPU (Tctl/Tdie)", "°C")
+	addReading(cpu, hwinfoshmem.SENSOR_TYPE_TEMP, 0x1000008, "CPU CCD1 (Tdie)", "CPU CCD1 (Tdie)", "°C")
+	addReading(cpu, hwinfoshmem.SENSOR_TYPE_TEMP, 0x1000009, "CPU CCD2 (Tdie)", "CPU CCD2 (Tdie)", "°C")
+	addReading(cpu, hwinfoshmem.SENSOR_TYPE_POWER, 0x8000000, "CPU Package Power", "CPU Package Power", "W")
+
+	motherboard := addSensor(0xF0008689, 0, "GIGABYTE B650E AORUS MASTER (ITE IT8689E)", "Motherboard")
+	addReading(motherboard, hwinfoshmem.SENSOR_TYPE_TEMP, 0x1000005, "EC_TEMP1", "Water (EC_TEMP1)", "°C")
+	addReading(motherboard, hwinfoshmem.SENSOR_TYPE_FAN, 0x3000000, "CPU", "CPU Fan", "RPM")
+
+	gpu := addSensor(0xE0001800, 1, "GPU [#0]: AMD Radeon RX 7900 XTX", "GPU [#0]: AMD Radeon RX 7900 XTX")
+	addReading(gpu, hwinfoshmem.SENSOR_TYPE_TEMP, 0x100000A, "GPU Hot Spot Temperature", "GPU Hot Spot Temperature", "°C")
+
+	return snapshot
+}
+
+func labels(readings []*hwinfoshmem.Reading) []string {
+	result := make([]string, len(readings))
+	for i, reading := range readings {
+		result[i] = reading.UserLabel
+	}
+
+	return result
+}
+
+func ExampleCompile() {
+	cpuDies, err := selector.Compile(`sensor~"CPU \[#0\]*" && type==temp && label=~"CCD\d"`)
+	if err != nil {
+		fmt.Printf("Invalid selector: %s\n", err)
+		return
+	}
+
+	for _, reading := range cpuDies.Select(newTestSnapshot()) {
+		fmt.Println(reading.UserLabel)
+	}
+
+	// Output:
+	// CPU CCD1 (Tdie)
+	// CPU CCD2 (Tdie)
+}
+
+func TestSelector(t *testing.T) {
+	tests := []struct {
+		expression string
+		expected   []string
+	}{
+		{``, []string{"CPU (Tctl/Tdie)", "CPU CCD1 (Tdie)", "CPU CCD2 (Tdie)", "CPU Package Power", "Water (EC_TEMP1)", "CPU Fan", "GPU Hot Spot Temperature"}},
+		{`label == "CPU Fan"`, []string{"CPU Fan"}},
+		{`unit==W || unit==RPM`, []string{"CPU Package Power", "CPU Fan"}},
+		{`type==fan`, []string{"CPU Fan"}},
+		{`type==3`, []string{"CPU Fan"}},
+		{`type!=temp`, []string{"CPU Package Power", "CPU Fan"}},
+		{`sensor==Motherboard && original_label==EC_TEMP1`, []string{"Water (EC_TEMP1)"}},
+		{`sensor_original~"GIGABYTE*" && !(type==fan)`, []string{"Water (EC_TEMP1)"}},
+		{`sensor_id==0xe0001800 && instance==1`, []string{"GPU Hot Spot Temperature"}},
+		{`id==0x1000005 || id==16777225`, []string{"CPU CCD2 (Tdie)", "Water (EC_TEMP1)"}},
+		{`label~"CPU (*)"`, []string{"CPU (Tctl/Tdie)"}},
+		{`label~"CPU CCD?*"`, []string{"CPU CCD1 (Tdie)", "CPU CCD2 (Tdie)"}},
+		{`label~"CPU CCD[!1]*"`, []string{"CPU CCD2 (Tdie)"}},
+		{`label~"CPU"`, []string{}},
+		{`label=~"(?i)water"`, []string{"Water (EC_TEMP1)"}},
+		{`label=~"^CPU (C|P)"`, []string{"CPU CCD1 (Tdie)", "CPU CCD2 (Tdie)", "CPU Package Power"}},
+		{`label=~"^CPU$"`, []string{}},
+		{`type==temp && unit=="°C" || type==power`, []string{"CPU (Tctl/Tdie)", "CPU CCD1 (Tdie)", "CPU CCD2 (Tdie)", "CPU Package Power", "Water (EC_TEMP1)", "GPU Hot Spot Temperature"}},
+		{`type==temp && (sensor==Motherboard || instance==1)`, []string{"Water (EC_TEMP1)", "GPU Hot Spot Temperature"}},
+		{`label=="quote \" and backslash \\"`, []string{}},
+	}
+
+	snapshot := newTestSnapshot()
+
+	for _, test := range tests {
+		compiled, err := selector.Compile(test.expression)
+		if err != nil {
+			t.Errorf("failed to compile %s: %v", test.expression, err)
+			continue
+		}
+
+		result := labels(compiled.Select(snapshot))
+		if fmt.Sprint(result) != fmt.Sprint(test.expected) {
+			t.Errorf("%s: expected %q, got %q", test.expression, test.expected, result)
+		}
+	}
+}
+
+func TestSelectorSyntaxErrors(t *testing.T) {
+	invalid := []string{
+		`label`,
+		`label==`,
+		`name==x`,
+		`label=="unterminated`,
+		`label==x &&`,
+		`(label==x`,
+		`label==x)`,
+		`id~"1*"`,
+		`id==x`,
+		`type==warm`,
+		`label=~"("`,
+		`label # x`,
+	}
+
+	for _, expression := range invalid {
+		if _, err := selector.Compile(expression); !errors.Is(err, selector.ErrSyntax) {
+			t.Errorf("expected ErrSyntax for %s, got %v", expression, err)
+		}
+	}
+}
+
+func TestSelectorFlag(t *testing.T) {
+	var selected selector.Selector
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags.Var(&selected, "select", "readings to select")
+
+	if err := flags.Parse([]string{"-select", "type==fan"}); err != nil {
+		t.Fatalf("failed to parse flags: %v", err)
+	}
+
+	if result := labels(selected.Select(newTestSnapshot())); len(result) != 1 || result[0] != "CPU Fan" {
+		t.Errorf("unexpected readings %q", result)
+	}
+}
@@ -0,0 +1,12 @@
+/*
+Package units interprets the free-text units of HWiNFO readings, e.g. °C, °F, MHz, MB/s, or Yes/No.
+
+HWiNFO reports values in the units configured by the user, e.g. temperatures in °F rather than
+°C. [Parse] determines the [Dimension] of a unit and how its values relate to the base unit of that
+dimension, which allows converting values using [Convert] or [Unit.ToBase].
+Readings can be converted to base units as a whole using [NormalizeReading].
+
+Base units are SI units with a few exceptions that match how HWiNFO and monitoring systems report
+values: temperatures are in °C, rotation speeds in RPM, ratios in %, and data in bytes.
+*/
+package units
@@ -0,0 +1,47 @@
+package units
+
+import (
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/hwinfoshmem"
+)
+
+// NormalizedReading contains the values of a reading converted to the base unit of its dimension.
+type NormalizedReading struct {
+	// The unit the reading was reported in.
+	Unit Unit
+
+	// The unit of the values, see [Dimension.BaseUnit]. When the unit of the reading has no base
+	// unit, e.g. because it is unknown, this is the unit of the reading and the values are
+	// unchanged.
+	BaseUnit string
+
+	Value float64
+	Min   float64
+	Max   float64
+	Avg   float64
+}
+
+// NormalizeReading converts the values of the reading to the base unit of its dimension, making
+// readings comparable regardless of the units HWiNFO was configured to use.
+func NormalizeReading(reading *hwinfoshmem.Reading) NormalizedReading {
+	unit := Parse(reading.Unit)
+	baseUnit := unit.Dimension.BaseUnit()
+	if baseUnit == "" {
+		return NormalizedReading{
+			Unit:     unit,
+			BaseUnit: reading.Unit,
+			Value:    reading.Value,
+			Min:      reading.ValueMin,
+			Max:      reading.ValueMax,
+			Avg:      reading.ValueAvg,
+		}
+	}
+
+	return NormalizedReading{
+		Unit:     unit,
+		BaseUnit: baseUnit,
+		Value:    unit.ToBase(reading.Value),
+		Min:      unit.ToBase(reading.ValueMin),
+		Max:      unit.ToBase(reading.ValueMax),
+		Avg:      unit.ToBase(reading.ValueAvg),
+	}
+}
@@ -0,0 +1,288 @@
+package units
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrIncompatible is returned when converting between units of different dimensions.
+var ErrIncompatible = errors.New("incompatible units")
+
+// Dimension is the physical quantity measured by a unit.
+type Dimension int
+
+const (
+	// DimensionUnknown is used for units that are not recognized. Values are not converted.
+	DimensionUnknown Dimension = iota
+	DimensionTemperature
+	DimensionVoltage
+	DimensionCurrent
+	DimensionPower
+	DimensionEnergy
+	DimensionFrequency
+	DimensionRotationSpeed
+	DimensionRatio
+	DimensionData
+	DimensionDataRate
+	DimensionTime
+
+	// DimensionBoolean is used for readings that are either 0 or 1, e.g. with unit Yes/No.
+	DimensionBoolean
+
+	// DimensionEnum is used for readings of which the value is one of a few states, e.g. with
+	// unit AC/DC/Battery.
+	DimensionEnum
+)
+
+// dimensionInfo contains the metadata of a dimension.
+type dimensionInfo struct {
+	name         string
+	baseUnit     string
+	metricSuffix string
+}
+
+var dimensionInfos = map[Dimension]dimensionInfo{
+	DimensionUnknown:       {name: "unknown"},
+	DimensionTemperature:   {name: "temperature", baseUnit: "°C", metricSuffix: "celsius"},
+	DimensionVoltage:       {name: "voltage", baseUnit: "V", metricSuffix: "volts"},
+	DimensionCurrent:       {name: "current", baseUnit: "A", metricSuffix: "amperes"},
+	DimensionPower:         {name: "power", baseUnit: "W", metricSuffix: "watts"},
+	DimensionEnergy:        {name: "energy", baseUnit: "J", metricSuffix: "joules"},
+	DimensionFrequency:     {name: "frequency", baseUnit: "Hz", metricSuffix: "hertz"},
+	DimensionRotationSpeed: {name: "rotation speed", baseUnit: "RPM", metricSuffix: "rpm"},
+	DimensionRatio:         {name: "ratio", baseUnit: "%", metricSuffix: "percent"},
+	DimensionData:          {name: "data", baseUnit: "B", metricSuffix: "bytes"},
+	DimensionDataRate:      {name: "data rate", baseUnit: "B/s", metricSuffix: "bytes_per_second"},
+	DimensionTime:          {name: "time", baseUnit: "s", metricSuffix: "seconds"},
+	DimensionBoolean:       {name: "boolean"},
+	DimensionEnum:          {name: "enum"},
+}
+
+func (dimension Dimension) String() string {
+	if info, ok := dimensionInfos[dimension]; ok {
+		return info.name
+	}
+
+	return fmt.Sprintf("Dimension(%d)", int(dimension))
+}
+
+// BaseUnit returns the unit that values of the dimension are converted to by [Unit.ToBase].
+// Temperatures use °C rather than kelvin as that is what HWiNFO and most monitoring systems use.
+// Returns an empty string for dimensions without a unit.
+func (dimension Dimension) BaseUnit() string {
+	return dimensionInfos[dimension].baseUnit
+}
+
+// MetricSuffix returns the suffix for metric names of values in the base unit, e.g. celsius or
+// bytes_per_second. The suffixes match [hwinfoshmem.ReadingType.MetricSuffix].
+// Returns an empty string for dimensions without a unit.
+func (dimension Dimension) MetricSuffix() string {
+	return dimensionInfos[dimension].metricSuffix
+}
+
+// Unit is a parsed unit. A value in this unit equals value*Scale+Offset in the base unit of the
+// dimension.
+//
+// Unit has an initializer function, [Parse].
+type Unit struct {
+	// The unit as it was parsed, e.g. MHz.
+	Symbol string
+
+	Dimension Dimension
+	Scale     float64
+	Offset    float64
+}
+
+// knownUnits are the units that can be parsed, by symbol.
+var knownUnits = map[string]Unit{
+	"°C": {Dimension: DimensionTemperature, Scale: 1},
+	"℃":  {Dimension: DimensionTemperature, Scale: 1},
+	"°F": {Dimension: DimensionTemperature, Scale: 5.0 / 9.0, Offset: -32 * 5.0 / 9.0},
+	"℉":  {Dimension: DimensionTemperature, Scale: 5.0 / 9.0, Offset: -32 * 5.0 / 9.0},
+	"K":  {Dimension: DimensionTemperature, Scale: 1, Offset: -273.15},
+
+	"V":  {Dimension: DimensionVoltage, Scale: 1},
+	"mV": {Dimension: DimensionVoltage, Scale: 1e-3},
+
+	"A":  {Dimension: DimensionCurrent, Scale: 1},
+	"mA": {Dimension: DimensionCurrent, Scale: 1e-3},
+
+	"W":  {Dimension: DimensionPower, Scale: 1},
+	"mW": {Dimension: DimensionPower, Scale: 1e-3},
+	"kW": {Dimension: DimensionPower, Scale: 1e3},
+
+	"J":   {Dimension: DimensionEnergy, Scale: 1},
+	"Wh":  {Dimension: DimensionEnergy, Scale: 3600},
+	"mWh": {Dimension: DimensionEnergy, Scale: 3.6},
+	"kWh": {Dimension: DimensionEnergy, Scale: 3.6e6},
+
+	"Hz":  {Dimension: DimensionFrequency, Scale: 1},
+	"kHz": {Dimension: DimensionFrequency, Scale: 1e3},
+	"MHz": {Dimension: DimensionFrequency, Scale: 1e6},
+	"GHz": {Dimension: DimensionFrequency, Scale: 1e9},
+	"FPS": {Dimension: DimensionFrequency, Scale: 1},
+
+	"RPM": {Dimension: DimensionRotationSpeed, Scale: 1},
+
+	"%": {Dimension: DimensionRatio, Scale: 1},
+
+	"s":   {Dimension: DimensionTime, Scale: 1},
+	"ms":  {Dimension: DimensionTime, Scale: 1e-3},
+	"µs":  {Dimension: DimensionTime, Scale: 1e-6},
+	"us":  {Dimension: DimensionTime, Scale: 1e-6},
+	"min": {Dimension: DimensionTime, Scale: 60},
+	"h":   {Dimension: DimensionTime, Scale: 3600},
+}
+
+// dataPrefixes are the multipliers of the data units. Like Windows and HWiNFO, the prefixes
+// without i are powers of 1024 as well.
+var dataPrefixes = map[string]float64{
+	"":  1,
+	"K": 1 << 10,
+	"k": 1 << 10,
+	"M": 1 << 20,
+	"G": 1 << 30,
+	"T": 1 << 40,
+	"P": 1 << 50,
+}
+
+// booleanUnits are the units of readings that are either 0 or 1.
+var booleanUnits = map[string]bool{
+	"yes/no":     true,
+	"no/yes":     true,
+	"on/off":     true,
+	"true/false": true,
+}
+
+// Parse interprets the unit of a reading.
+// Units that are not recognized have [DimensionUnknown] and a scale of 1, which leaves values
+// unchanged.
+//
+// Data units, e.g. MB and GB/s, are interpreted as powers of 1024, like Windows and HWiNFO do.
+// Data units using bits, e.g. Mbps and Gbit/s, are converted to bytes.
+// Units that consist of words separated by slashes, e.g. Yes/No, are booleans or enums.
+func Parse(symbol string) Unit {
+	trimmed := strings.TrimSpace(symbol)
+
+	if unit, ok := knownUnits[trimmed]; ok {
+		unit.Symbol = symbol
+		return unit
+	}
+
+	if unit, ok := parseData(trimmed); ok {
+		unit.Symbol = symbol
+		return unit
+	}
+
+	if booleanUnits[strings.ToLower(trimmed)] {
+		return Unit{Symbol: symbol, Dimension: DimensionBoolean, Scale: 1}
+	}
+
+	if isEnum(trimmed) {
+		return Unit{Symbol: symbol, Dimension: DimensionEnum, Scale: 1}
+	}
+
+	return Unit{Symbol: symbol, Dimension: DimensionUnknown, Scale: 1}
+}
+
+// parseData parses units of data and data rates, e.g. MB, GiB, KB/s, and Mbps.
+func parseData(symbol string) (Unit, bool) {
+	dimension := DimensionData
+	for _, suffix := range []string{"/s", "ps"} {
+		if strings.HasSuffix(symbol, suffix) {
+			symbol = strings.TrimSuffix(symbol, suffix)
+			dimension = DimensionDataRate
+			break
+		}
+	}
+
+	scale := 1.0
+	switch {
+	case strings.HasSuffix(symbol, "bit"):
+		symbol = strings.TrimSuffix(symbol, "bit")
+		scale = 1.0 / 8
+	case strings.HasSuffix(symbol, "b"):
+		symbol = strings.TrimSuffix(symbol, "b")
+		scale = 1.0 / 8
+	case strings.HasSuffix(symbol, "B"):
+		symbol = strings.TrimSuffix(symbol, "B")
+	default:
+		return Unit{}, false
+	}
+
+	symbol = strings.TrimSuffix(symbol, "i")
+	multiplier, ok := dataPrefixes[symbol]
+	if !ok {
+		return Unit{}, false
+	}
+
+	return Unit{Dimension: dimension, Scale: scale * multiplier}, true
+}
+
+// isEnum reports whether the unit consists of at least two words separated by slashes.
+// Rates such as GT/s are not enums.
+func isEnum(symbol string) bool {
+	states := strings.Split(symbol, "/")
+	if len(states) < 2 || states[len(states)-1] == "s" {
+		return false
+	}
+
+	for _, state := range states {
+		if state == "" || strings.ContainsAny(state, " 0123456789") {
+			return false
+		}
+	}
+
+	return true
+}
+
+// IsBoolean reports whether readings with this unit are either 0 or 1.
+func (unit Unit) IsBoolean() bool {
+	return unit.Dimension == DimensionBoolean
+}
+
+// IsEnum reports whether readings with this unit are one of a few states.
+func (unit Unit) IsEnum() bool {
+	return unit.Dimension == DimensionEnum
+}
+
+// IsKnown reports whether the unit was recognized.
+func (unit Unit) IsKnown() bool {
+	return unit.Dimension != DimensionUnknown
+}
+
+// ToBase converts a value in this unit to the base unit of its dimension, see
+// [Dimension.BaseUnit].
+func (unit Unit) ToBase(value float64) float64 {
+	return value*unit.Scale + unit.Offset
+}
+
+// FromBase converts a value in the base unit of the dimension to this unit.
+func (unit Unit) FromBase(value float64) float64 {
+	return (value - unit.Offset) / unit.Scale
+}
+
+// Convert converts a value from one unit to another.
+// Returns an error wrapping [ErrIncompatible] when the units have different dimensions or when
+// either unit is unknown and they differ.
+func Convert(value float64, from Unit, to Unit) (float64, error) {
+	if from.Dimension != to.Dimension || (from.Dimension == DimensionUnknown && from.Symbol != to.Symbol) {
+		return 0, fmt.Errorf("%w: %q (%s) and %q (%s)", ErrIncompatible, from.Symbol, from.Dimension, to.Symbol, to.Dimension)
+	}
+
+	return to.FromBase(from.ToBase(value)), nil
+}
+
+// ToBase parses the unit and converts the value to the base unit of its dimension.
+// Returns the converted value and the base unit, or the value and unit unchanged when the unit
+// has no base unit.
+func ToBase(value float64, symbol string) (float64, string) {
+	unit := Parse(symbol)
+	baseUnit := unit.Dimension.BaseUnit()
+	if baseUnit == "" {
+		return value, symbol
+	}
+
+	return unit.ToBase(value), baseUnit
+}
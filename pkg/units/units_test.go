@@ -0,0 +1,152 @@
+package units_test
+
+import (
+	"errors"
+	"fmt"
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/hwinfoshmem"
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/units"
+	"math"
+	"testing"
+)
+
+func ExampleConvert() {
+	celsius, err := units.Convert(212, units.Parse("°F"), units.Parse("°C"))
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Printf("%.1f\n", celsius)
+
+	// Output:
+	// 100.0
+}
+
+func ExampleNormalizeReading() {
+	normalized := units.NormalizeReading(&hwinfoshmem.Reading{
+		Unit:     "MHz",
+		Value:    4200,
+		ValueMin: 550,
+		ValueMax: 5050,
+		ValueAvg: 3800,
+	})
+
+	fmt.Printf("%g %g %g %g %s\n", normalized.Value, normalized.Min, normalized.Max, normalized.Avg, normalized.BaseUnit)
+
+	// Output:
+	// 4.2e+09 5.5e+08 5.05e+09 3.8e+09 Hz
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		symbol    string
+		dimension units.Dimension
+		value     float64
+		base      float64
+	}{
+		{"°C", units.DimensionTemperature, 45, 45},
+		{"°F", units.DimensionTemperature, 113, 45},
+		{"K", units.DimensionTemperature, 318.15, 45},
+		{"mV", units.DimensionVoltage, 1250, 1.25},
+		{"W", units.DimensionPower, 65, 65},
+		{"mWh", units.DimensionEnergy, 1000, 3600},
+		{"MHz", units.DimensionFrequency, 4200, 4.2e9},
+		{"GHz", units.DimensionFrequency, 1.5, 1.5e9},
+		{"RPM", units.DimensionRotationSpeed, 1200, 1200},
+		{"%", units.DimensionRatio, 50, 50},
+		{"KB", units.DimensionData, 2, 2048},
+		{"MB", units.DimensionData, 1, 1 << 20},
+		{"GiB", units.DimensionData, 1, 1 << 30},
+		{"MB/s", units.DimensionDataRate, 3, 3 << 20},
+		{"Mbps", units.DimensionDataRate, 8, 1 << 20},
+		{"Gbit/s", units.DimensionDataRate, 8, 1 << 30},
+		{"ms", units.DimensionTime, 250, 0.25},
+		{" W ", units.DimensionPower, 1, 1},
+		{"Yes/No", units.DimensionBoolean, 1, 1},
+		{"AC/DC/Battery", units.DimensionEnum, 2, 2},
+		{"x", units.DimensionUnknown, 36, 36},
+		{"", units.DimensionUnknown, 3, 3},
+		{"T/1s", units.DimensionUnknown, 3, 3},
+		{"GT/s", units.DimensionUnknown, 16, 16},
+	}
+
+	for _, test := range tests {
+		unit := units.Parse(test.symbol)
+		if unit.Symbol != test.symbol {
+			t.Errorf("expected symbol %q, got %q", test.symbol, unit.Symbol)
+		}
+
+		if unit.Dimension != test.dimension {
+			t.Errorf("expected dimension %s for %q, got %s", test.dimension, test.symbol, unit.Dimension)
+		}
+
+		base := unit.ToBase(test.value)
+		if math.Abs(base-test.base) > 1e-9*math.Max(1, math.Abs(test.base)) {
+			t.Errorf("expected %g %q to be %g in base unit, got %g", test.value, test.symbol, test.base, base)
+		}
+
+		if back := unit.FromBase(base); math.Abs(back-test.value) > 1e-9*math.Max(1, math.Abs(test.value)) {
+			t.Errorf("expected %g %q to round trip, got %g", test.value, test.symbol, back)
+		}
+	}
+}
+
+func TestUnitFlags(t *testing.T) {
+	if !units.Parse("Yes/No").IsBoolean() || units.Parse("AC/DC").IsBoolean() {
+		t.Error("expected only Yes/No to be a boolean")
+	}
+
+	if !units.Parse("AC/DC").IsEnum() || units.Parse("Yes/No").IsEnum() || units.Parse("MB/s").IsEnum() {
+		t.Error("expected only AC/DC to be an enum")
+	}
+
+	if units.Parse("x").IsKnown() || !units.Parse("V").IsKnown() {
+		t.Error("expected only V to be known")
+	}
+}
+
+func TestConvert(t *testing.T) {
+	if value, err := units.Convert(2, units.Parse("GB"), units.Parse("MB")); err != nil || value != 2048 {
+		t.Errorf("expected 2048, got %g, %v", value, err)
+	}
+
+	if value, err := units.Convert(3, units.Parse("x"), units.Parse("x")); err != nil || value != 3 {
+		t.Errorf("expected 3, got %g, %v", value, err)
+	}
+
+	for _, pair := range [][2]string{{"°C", "V"}, {"x", "T"}, {"Yes/No", "%"}} {
+		_, err := units.Convert(1, units.Parse(pair[0]), units.Parse(pair[1]))
+		if !errors.Is(err, units.ErrIncompatible) {
+			t.Errorf("expected ErrIncompatible converting %q to %q, got %v", pair[0], pair[1], err)
+		}
+	}
+}
+
+func TestBaseUnitMatchesReadingType(t *testing.T) {
+	symbols := map[hwinfoshmem.ReadingType]string{
+		hwinfoshmem.SENSOR_TYPE_TEMP:    "°F",
+		hwinfoshmem.SENSOR_TYPE_VOLT:    "mV",
+		hwinfoshmem.SENSOR_TYPE_FAN:     "RPM",
+		hwinfoshmem.SENSOR_TYPE_CURRENT: "mA",
+		hwinfoshmem.SENSOR_TYPE_POWER:   "mW",
+		hwinfoshmem.SENSOR_TYPE_CLOCK:   "MHz",
+		hwinfoshmem.SENSOR_TYPE_USAGE:   "%",
+	}
+
+	for readingType, symbol := range symbols {
+		dimension := units.Parse(symbol).Dimension
+		if dimension.BaseUnit() != readingType.CanonicalUnit() {
+			t.Errorf("expected base unit of %s to be %q, got %q", readingType, readingType.CanonicalUnit(), dimension.BaseUnit())
+		}
+
+		if dimension.MetricSuffix() != readingType.MetricSuffix() {
+			t.Errorf("expected metric suffix of %s to be %q, got %q", readingType, readingType.MetricSuffix(), dimension.MetricSuffix())
+		}
+	}
+}
+
+func TestNormalizeReadingUnknownUnit(t *testing.T) {
+	normalized := units.NormalizeReading(&hwinfoshmem.Reading{Unit: "x", Value: 36, ValueMax: 48})
+	if normalized.BaseUnit != "x" || normalized.Value != 36 || normalized.Max != 48 {
+		t.Errorf("expected unknown unit to be unchanged, got %+v", normalized)
+	}
+}
@@ -1,6 +1,9 @@
 package bytesutil
 
-import "bytes"
+import (
+	"bytes"
+	"unicode/utf8"
+)
 
 // Utf8BytesToString converts UTF-8 bytes to a string stopping at the first nul byte.
 func Utf8BytesToString(data []byte) string {
@@ -11,3 +14,21 @@ func Utf8BytesToString(data []byte) string {
 		return string(data[:nulByteIndex])
 	}
 }
+
+// StringToUtf8Bytes copies the string into data and pads the rest of data with nul bytes.
+// A string that does not fit is truncated without splitting a multibyte character.
+// Returns the amount of bytes of the string that were copied.
+func StringToUtf8Bytes(data []byte, s string) int {
+	length := len(s)
+	if length > len(data) {
+		length = len(data)
+		for length > 0 && !utf8.RuneStart(s[length]) {
+			length--
+		}
+	}
+
+	copy(data, s[:length])
+	clear(data[length:])
+
+	return length
+}
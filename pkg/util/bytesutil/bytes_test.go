@@ -44,3 +44,18 @@ func ExampleUtf8BytesToString() {
 	// string(byteArray[:])            "Hello\x00\x00\x00"    8
 	// Utf8BytesToString(byteArray)    "Hello"                5
 }
+
+func ExampleStringToUtf8Bytes() {
+	theBytes := make([]byte, 8)
+
+	bytesutil.StringToUtf8Bytes(theBytes, "°C")
+	fmt.Printf("% 02x\n", theBytes)
+
+	// The last character does not fit and is omitted instead of split.
+	bytesutil.StringToUtf8Bytes(theBytes, "1234567°")
+	fmt.Printf("% 02x\n", theBytes)
+
+	// Output:
+	// c2 b0 43 00 00 00 00 00
+	// 31 32 33 34 35 36 37 00
+}
@@ -0,0 +1,181 @@
+package bytesutil
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/korean"
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/encoding/traditionalchinese"
+	"golang.org/x/text/encoding/unicode"
+	"io"
+	"unicode/utf8"
+)
+
+// ErrUnsupportedCodepage is returned when converting using a codepage that is not supported.
+var ErrUnsupportedCodepage = errors.New("unsupported codepage")
+
+// Codepage is the identifier of a Windows codepage, also called ANSI codepage, which determines how
+// the characters of an extended ASCII string are encoded.
+//
+// Get the codepage used by a Windows system using this powershell command:
+//
+//	[System.Text.Encoding]::Default
+type Codepage uint32
+
+const (
+	Codepage437   Codepage = 437   // OEM United States
+	Codepage850   Codepage = 850   // OEM Multilingual Latin 1
+	Codepage866   Codepage = 866   // OEM Russian
+	Codepage874   Codepage = 874   // Thai
+	Codepage932   Codepage = 932   // Japanese (Shift-JIS)
+	Codepage936   Codepage = 936   // Simplified Chinese (GBK)
+	Codepage949   Codepage = 949   // Korean
+	Codepage950   Codepage = 950   // Traditional Chinese (Big5)
+	Codepage1250  Codepage = 1250  // Central European
+	Codepage1251  Codepage = 1251  // Cyrillic
+	Codepage1252  Codepage = 1252  // Western European
+	Codepage1253  Codepage = 1253  // Greek
+	Codepage1254  Codepage = 1254  // Turkish
+	Codepage1255  Codepage = 1255  // Hebrew
+	Codepage1256  Codepage = 1256  // Arabic
+	Codepage1257  Codepage = 1257  // Baltic
+	Codepage1258  Codepage = 1258  // Vietnamese
+	Codepage65001 Codepage = 65001 // UTF-8
+)
+
+var codepageEncodings = map[Codepage]encoding.Encoding{
+	Codepage437:   charmap.CodePage437,
+	Codepage850:   charmap.CodePage850,
+	Codepage866:   charmap.CodePage866,
+	Codepage874:   charmap.Windows874,
+	Codepage932:   japanese.ShiftJIS,
+	Codepage936:   simplifiedchinese.GBK,
+	Codepage949:   korean.EUCKR,
+	Codepage950:   traditionalchinese.Big5,
+	Codepage1250:  charmap.Windows1250,
+	Codepage1251:  charmap.Windows1251,
+	Codepage1252:  charmap.Windows1252,
+	Codepage1253:  charmap.Windows1253,
+	Codepage1254:  charmap.Windows1254,
+	Codepage1255:  charmap.Windows1255,
+	Codepage1256:  charmap.Windows1256,
+	Codepage1257:  charmap.Windows1257,
+	Codepage1258:  charmap.Windows1258,
+	Codepage65001: unicode.UTF8,
+}
+
+// IsSupported reports whether strings can be converted using the codepage.
+func (codepage Codepage) IsSupported() bool {
+	_, ok := codepageEncodings[codepage]
+	return ok
+}
+
+func (codepage Codepage) String() string {
+	return fmt.Sprintf("CP%d", uint32(codepage))
+}
+
+func (codepage Codepage) getEncoding() (encoding.Encoding, error) {
+	enc, ok := codepageEncodings[codepage]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedCodepage, codepage)
+	}
+
+	return enc, nil
+}
+
+// AnsiBytesToString converts bytes encoded using the codepage to a string stopping at the first
+// nul byte.
+// Bytes that are invalid in the codepage are replaced by the Unicode replacement character.
+func AnsiBytesToString(data []byte, codepage Codepage) (string, error) {
+	enc, err := codepage.getEncoding()
+	if err != nil {
+		return "", err
+	}
+
+	if nulByteIndex := bytes.IndexByte(data, 0); nulByteIndex >= 0 {
+		data = data[:nulByteIndex]
+	}
+
+	result, err := enc.NewDecoder().Bytes(data)
+	if err != nil {
+		return "", fmt.Errorf("error decoding %s: %w", codepage, err)
+	}
+
+	return string(result), nil
+}
+
+// StringToAnsiBytes encodes the string using the codepage, copies it into data and pads the rest
+// of data with nul bytes.
+// Characters that do not exist in the codepage are replaced by a question mark.
+// A string that does not fit is truncated without splitting a multibyte character.
+// Returns the amount of bytes that were written before padding.
+func StringToAnsiBytes(data []byte, s string, codepage Codepage) (int, error) {
+	enc, err := codepage.getEncoding()
+	if err != nil {
+		return 0, err
+	}
+
+	encoder := enc.NewEncoder()
+	length := 0
+	runeBytes := make([]byte, utf8.UTFMax)
+
+	for _, r := range s {
+		encoded := encodeRune(encoder, runeBytes, r)
+		if length+len(encoded) > len(data) {
+			break
+		}
+
+		length += copy(data[length:], encoded)
+	}
+
+	clear(data[length:])
+
+	return length, nil
+}
+
+// NewAnsiReader returns a reader that converts the text read from reader from the codepage to
+// UTF-8.
+// Bytes that are invalid in the codepage are replaced by the Unicode replacement character.
+func NewAnsiReader(reader io.Reader, codepage Codepage) (io.Reader, error) {
+	enc, err := codepage.getEncoding()
+	if err != nil {
+		return nil, err
+	}
+
+	return enc.NewDecoder().Reader(reader), nil
+}
+
+// StringToAnsi encodes the string using the codepage.
+// Characters that do not exist in the codepage are replaced by a question mark.
+func StringToAnsi(s string, codepage Codepage) ([]byte, error) {
+	enc, err := codepage.getEncoding()
+	if err != nil {
+		return nil, err
+	}
+
+	encoder := enc.NewEncoder()
+	result := make([]byte, 0, len(s))
+	runeBytes := make([]byte, utf8.UTFMax)
+
+	for _, r := range s {
+		result = append(result, encodeRune(encoder, runeBytes, r)...)
+	}
+
+	return result, nil
+}
+
+// encodeRune encodes the rune using the encoder, or returns a question mark when the rune does
+// not exist in the codepage. runeBytes is used to convert the rune to UTF-8 and must be at least
+// [utf8.UTFMax] bytes long.
+func encodeRune(encoder *encoding.Encoder, runeBytes []byte, r rune) []byte {
+	encoded, err := encoder.Bytes(runeBytes[:utf8.EncodeRune(runeBytes, r)])
+	if err != nil || r == utf8.RuneError {
+		return []byte{'?'}
+	}
+
+	return encoded
+}
@@ -0,0 +1,111 @@
+package bytesutil_test
+
+import (
+	"errors"
+	"fmt"
+	"github.com/MatthiasKunnen/hwinfo-go/pkg/util/bytesutil"
+	"io"
+	"strings"
+	"testing"
+)
+
+func ExampleAnsiBytesToString() {
+	theBytes := []byte{0xB0, 0x43, 0, 0}
+
+	western, _ := bytesutil.AnsiBytesToString(theBytes, bytesutil.Codepage1252)
+	cyrillic, _ := bytesutil.AnsiBytesToString(theBytes, bytesutil.Codepage1251)
+
+	fmt.Printf("%s: %q\n", bytesutil.Codepage1252, western)
+	fmt.Printf("%s: %q\n", bytesutil.Codepage1251, cyrillic)
+
+	// Output:
+	// CP1252: "°C"
+	// CP1251: "°C"
+}
+
+func TestAnsiBytesToString(t *testing.T) {
+	tests := []struct {
+		codepage bytesutil.Codepage
+		bytes    []byte
+		expected string
+	}{
+		{bytesutil.Codepage1252, []byte("Temp\xe9rature\x00garbage"), "Température"},
+		{bytesutil.Codepage1250, []byte("Teplota j\xe1dra"), "Teplota jádra"},
+		{bytesutil.Codepage1251, []byte("\xd2\xe5\xec\xef\xe5\xf0\xe0\xf2\xf3\xf0\xe0"), "Температура"},
+		{bytesutil.Codepage932, []byte("\x89\xb7\x93\x78"), "温度"},
+		{bytesutil.Codepage65001, []byte("°C\x00\x00"), "°C"},
+	}
+
+	for _, test := range tests {
+		result, err := bytesutil.AnsiBytesToString(test.bytes, test.codepage)
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", test.codepage, err)
+		}
+
+		if result != test.expected {
+			t.Errorf("%s: expected %q, got %q", test.codepage, test.expected, result)
+		}
+	}
+}
+
+func TestStringToAnsiBytes(t *testing.T) {
+	data := make([]byte, 5)
+
+	length, err := bytesutil.StringToAnsiBytes(data, "°C", bytesutil.Codepage1252)
+	if err != nil || length != 2 || string(data) != "\xb0C\x00\x00\x00" {
+		t.Errorf("unexpected result %d, %q, %v", length, data, err)
+	}
+
+	// Characters that do not exist in the codepage are replaced.
+	length, err = bytesutil.StringToAnsiBytes(data, "温°C", bytesutil.Codepage1252)
+	if err != nil || length != 3 || string(data) != "?\xb0C\x00\x00" {
+		t.Errorf("unexpected result %d, %q, %v", length, data, err)
+	}
+
+	// Double byte characters are not split.
+	length, err = bytesutil.StringToAnsiBytes(data, "温度度", bytesutil.Codepage932)
+	if err != nil || length != 4 || string(data) != "\x89\xb7\x93\x78\x00" {
+		t.Errorf("unexpected result %d, %q, %v", length, data, err)
+	}
+}
+
+func TestNewAnsiReader(t *testing.T) {
+	reader, err := bytesutil.NewAnsiReader(strings.NewReader("CPU [\xb0C]\nGPU [\xb0F]"), bytesutil.Codepage1252)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	result, err := io.ReadAll(reader)
+	if err != nil || string(result) != "CPU [°C]\nGPU [°F]" {
+		t.Errorf("unexpected result %q, %v", result, err)
+	}
+}
+
+func TestStringToAnsi(t *testing.T) {
+	result, err := bytesutil.StringToAnsi("CPU [°C] ✓", bytesutil.Codepage1252)
+	if err != nil || string(result) != "CPU [\xb0C] ?" {
+		t.Errorf("unexpected result %q, %v", result, err)
+	}
+}
+
+func TestUnsupportedCodepage(t *testing.T) {
+	_, err := bytesutil.AnsiBytesToString([]byte("C"), bytesutil.Codepage(12345))
+	if !errors.Is(err, bytesutil.ErrUnsupportedCodepage) {
+		t.Errorf("expected ErrUnsupportedCodepage, got %v", err)
+	}
+
+	_, err = bytesutil.StringToAnsiBytes(make([]byte, 1), "C", bytesutil.Codepage(12345))
+	if !errors.Is(err, bytesutil.ErrUnsupportedCodepage) {
+		t.Errorf("expected ErrUnsupportedCodepage, got %v", err)
+	}
+
+	_, err = bytesutil.NewAnsiReader(strings.NewReader("C"), bytesutil.Codepage(12345))
+	if !errors.Is(err, bytesutil.ErrUnsupportedCodepage) {
+		t.Errorf("expected ErrUnsupportedCodepage, got %v", err)
+	}
+
+	_, err = bytesutil.StringToAnsi("C", bytesutil.Codepage(12345))
+	if !errors.Is(err, bytesutil.ErrUnsupportedCodepage) {
+		t.Errorf("expected ErrUnsupportedCodepage, got %v", err)
+	}
+}